@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KalleDK/go-csv/internal/tag"
+)
+
+// method is a pair of unmarshal and marshal methods for a converter
+type method struct {
+	converter *converter
+	optional  bool
+}
+
+func (m method) suffix() string {
+	if m.optional {
+		return "Optional" + m.converter.Name
+	}
+	return m.converter.Name
+}
+
+// fieldType is the type of the field, a pointer if blank cells are decoded as nil
+func (m method) fieldType() string {
+	if m.optional {
+		return "*" + m.converter.Type
+	}
+	return m.converter.Type
+}
+
+/*
+generate returns the formatted source of a struct named typeName with a field for each column,
+and the methods needed to convert the columns the csv package can't convert on its own.
+*/
+func generate(columns []column, pkg string, typeName string, source string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	imports := map[string]bool{}
+	methods := []method{}
+	seen := map[method]bool{}
+
+	for _, c := range columns {
+		if c.Converter.Type == "time.Time" {
+			imports["time"] = true
+		}
+		if !c.needsMethods() {
+			continue
+		}
+
+		m := method{converter: c.Converter, optional: c.IsOptional}
+		if !seen[m] {
+			seen[m] = true
+			methods = append(methods, m)
+			for _, path := range c.Converter.Imports {
+				imports[path] = true
+			}
+		}
+	}
+
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Slice(paths, func(i, j int) bool {
+			if isStandard(paths[i]) != isStandard(paths[j]) {
+				return isStandard(paths[i])
+			}
+			return paths[i] < paths[j]
+		})
+
+		// The standard library first, like goimports
+		buf.WriteString("import (\n")
+		for i, path := range paths {
+			if i > 0 && isStandard(paths[i-1]) && !isStandard(path) {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(&buf, "%q\n", path)
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	fmt.Fprintf(&buf, "// %s is a record of %s, generated by csv2struct\n", typeName, source)
+	fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+	for _, c := range columns {
+		writeField(&buf, c)
+	}
+	buf.WriteString("}\n")
+
+	for _, m := range methods {
+		writeMethods(&buf, typeName, m)
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// isStandard tells if the import path is in the standard library, which has no dots in the first element
+func isStandard(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func writeField(buf *bytes.Buffer, c column) {
+	elements := []string{tag.QuoteName(c.Header)}
+	if !c.IsOptional {
+		elements = append(elements, "required")
+	}
+
+	fieldType := c.Converter.Type
+	if c.needsMethods() {
+		m := method{converter: c.Converter, optional: c.IsOptional}
+		elements = append(elements, "unmarshal=Unmarshal"+m.suffix(), "marshal=Marshal"+m.suffix())
+		fieldType = m.fieldType()
+	}
+
+	fmt.Fprintf(buf, "%s %s %s\n", c.Field, fieldType, tagLiteral(`csv:`+strconv.Quote(strings.Join(elements, ","))))
+}
+
+// tagLiteral writes the tag as a raw string, unless it holds a backquote
+func tagLiteral(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+func writeMethods(buf *bytes.Buffer, typeName string, m method) {
+	c := m.converter
+	suffix := m.suffix()
+
+	if m.optional {
+		fmt.Fprintf(buf, "\n// Unmarshal%s leaves the field nil for blank cells\n", suffix)
+		fmt.Fprintf(buf, "func (*%s) Unmarshal%s(v **%s, text []byte) error {\n", typeName, suffix, c.Type)
+		buf.WriteString("if len(text) == 0 {\n*v = nil\nreturn nil\n}\n")
+		writeParse(buf, c)
+		buf.WriteString("*v = &value\nreturn nil\n}\n")
+
+		fmt.Fprintf(buf, "\n// Marshal%s writes a blank cell for nil\n", suffix)
+		fmt.Fprintf(buf, "func (*%s) Marshal%s(v **%s) ([]byte, error) {\n", typeName, suffix, c.Type)
+		buf.WriteString("if *v == nil {\nreturn nil, nil\n}\n")
+		writeFormat(buf, c, "**v")
+		return
+	}
+
+	fmt.Fprintf(buf, "\n// Unmarshal%s reads the layout time.Time can't read on its own\n", suffix)
+	fmt.Fprintf(buf, "func (*%s) Unmarshal%s(v *%s, text []byte) error {\n", typeName, suffix, c.Type)
+	writeParse(buf, c)
+	buf.WriteString("*v = value\nreturn nil\n}\n")
+
+	fmt.Fprintf(buf, "\n// Marshal%s writes the layout read by Unmarshal%s\n", suffix, suffix)
+	fmt.Fprintf(buf, "func (*%s) Marshal%s(v *%s) ([]byte, error) {\n", typeName, suffix, c.Type)
+	writeFormat(buf, c, "*v")
+}
+
+// writeParse declares value, parsed from text and converted to the type of the converter
+func writeParse(buf *bytes.Buffer, c *converter) {
+	if c.convert == "" {
+		fmt.Fprintf(buf, "value, err := %s\nif err != nil {\nreturn err\n}\n", c.parse("string(text)"))
+		return
+	}
+
+	fmt.Fprintf(buf, "parsed, err := %s\nif err != nil {\nreturn err\n}\n", c.parse("string(text)"))
+	fmt.Fprintf(buf, "value := %s(parsed)\n", c.convert)
+}
+
+// writeFormat returns the text of value and closes the method
+func writeFormat(buf *bytes.Buffer, c *converter, value string) {
+	if c.formatErr {
+		fmt.Fprintf(buf, "text, err := %s\nreturn []byte(text), err\n}\n", c.format(value))
+		return
+	}
+
+	fmt.Fprintf(buf, "return []byte(%s), nil\n}\n", c.format(value))
+}
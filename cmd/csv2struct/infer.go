@@ -0,0 +1,240 @@
+package main
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+/*
+converter is a type a column can be inferred as. The parse and format expressions are used for the
+unmarshal and marshal methods generated when the csv package can't convert the type on its own.
+
+Columns are matched with the parser of the generated methods, which for the basic types are the
+converters of the csv package, so a column is never inferred as a type its own cells can't be read as.
+*/
+type converter struct {
+	Name    string
+	Type    string
+	Imports []string
+	native  bool
+	matches func(text string) bool
+	parse   func(text string) string
+	// convert is the conversion of the parsed value to Type, if parse returns another type
+	convert string
+	format  func(value string) string
+	// formatErr tells if format returns an error besides the text
+	formatErr bool
+}
+
+var boolConverter = &converter{
+	Name:    "Bool",
+	Type:    "bool",
+	native:  true,
+	Imports: []string{csvImport, "strconv"},
+	matches: func(text string) bool { _, err := csv.ParseBool(text); return err == nil },
+	parse:   func(text string) string { return "csv.ParseBool(" + text + ")" },
+	format:  func(value string) string { return "strconv.FormatBool(" + value + ")" },
+}
+
+var intConverter = &converter{
+	Name:    "Int",
+	Type:    "int",
+	native:  true,
+	Imports: []string{csvImport, "strconv"},
+	matches: func(text string) bool { _, err := csv.ParseInt(text, 0); return err == nil },
+	parse:   func(text string) string { return "csv.ParseInt(" + text + ", 0)" },
+	convert: "int",
+	format:  func(value string) string { return "strconv.Itoa(" + value + ")" },
+}
+
+var floatConverter = &converter{
+	Name:      "Float",
+	Type:      "float64",
+	native:    true,
+	Imports:   []string{csvImport},
+	matches:   func(text string) bool { _, err := csv.ParseFloat(text, 64); return err == nil },
+	parse:     func(text string) string { return "csv.ParseFloat(" + text + ", 64)" },
+	format:    func(value string) string { return "csv.FormatFloat(" + value + ", 64)" },
+	formatErr: true,
+}
+
+// csvImport is the import path of the csv package, used by the generated methods of the basic types
+const csvImport = "github.com/KalleDK/go-csv/csv"
+
+var stringConverter = &converter{
+	Name:    "String",
+	Type:    "string",
+	native:  true,
+	matches: func(string) bool { return true },
+}
+
+// timeConverter infers times in the layout, the layout is spelled as expr in the generated code
+func timeConverter(name string, layout string, expr string) *converter {
+	return &converter{
+		Name:    name,
+		Type:    "time.Time",
+		native:  layout == time.RFC3339,
+		Imports: []string{"time"},
+		matches: func(text string) bool { _, err := time.Parse(layout, text); return err == nil },
+		parse:   func(text string) string { return "time.Parse(" + expr + ", " + text + ")" },
+		format:  func(value string) string { return "(" + value + ").Format(" + expr + ")" },
+	}
+}
+
+/*
+converters in the order they are tried, the first one matching all the cells of a column is used.
+Times in RFC 3339 are read by time.Time itself, the other layouts need methods.
+*/
+var converters = []*converter{
+	boolConverter,
+	intConverter,
+	floatConverter,
+	timeConverter("Time", time.RFC3339, "time.RFC3339"),
+	timeConverter("DateTime", "2006-01-02 15:04:05", `"2006-01-02 15:04:05"`),
+	timeConverter("Date", "2006-01-02", `"2006-01-02"`),
+	timeConverter("USDate", "01/02/2006", `"01/02/2006"`),
+	timeConverter("EUDate", "02.01.2006", `"02.01.2006"`),
+	stringConverter,
+}
+
+type column struct {
+	Header     string
+	Field      string
+	Converter  *converter
+	IsOptional bool
+}
+
+/*
+needsMethods tells if the column needs generated methods. The csv package converts the native types
+on its own, but only strings can be read from blank cells.
+*/
+func (c *column) needsMethods() bool {
+	if c.Converter == stringConverter {
+		return false
+	}
+	return c.IsOptional || !c.Converter.native
+}
+
+// columnStats follows the converters still matching a column
+type columnStats struct {
+	candidates []*converter
+	blank      bool
+	values     int
+}
+
+func (s *columnStats) add(text string) {
+	if text == "" {
+		s.blank = true
+		return
+	}
+
+	s.values++
+	candidates := s.candidates[:0]
+	for _, c := range s.candidates {
+		if c.matches(text) {
+			candidates = append(candidates, c)
+		}
+	}
+	s.candidates = candidates
+}
+
+func (s *columnStats) converter() *converter {
+	// Without any values there is nothing to infer from
+	if s.values == 0 {
+		return stringConverter
+	}
+	return s.candidates[0]
+}
+
+/*
+infer reads up to limit records, or all of them if limit is 0, and infers the type of each column.
+Columns with blank cells are optional.
+*/
+func infer(reader *csv.Reader, limit int) ([]column, error) {
+	headers := reader.Headers()
+
+	stats := make([]columnStats, len(headers))
+	for i := range stats {
+		stats[i].candidates = append([]*converter{}, converters...)
+	}
+
+	for n := 0; limit == 0 || n < limit; n++ {
+		record, err := reader.ReadRecord()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range stats {
+			// Short records leave the remaining columns blank
+			text := ""
+			if i < record.Len() {
+				text = record.Field(i)
+			}
+			stats[i].add(text)
+		}
+	}
+
+	columns := make([]column, len(headers))
+	names := map[string]bool{}
+	for i, header := range headers {
+		columns[i] = column{
+			Header:     header,
+			Field:      uniqueIdentifier(header, i, names),
+			Converter:  stats[i].converter(),
+			IsOptional: stats[i].blank,
+		}
+	}
+
+	return columns, nil
+}
+
+/*
+identifier turns a header into an exported Go identifier, by upper casing the first letter of each word
+and leaving out everything but letters and digits.
+*/
+func identifier(header string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range header {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	name := b.String()
+	if name != "" && !unicode.IsLetter([]rune(name)[0]) {
+		name = "Column" + name
+	}
+
+	return name
+}
+
+// uniqueIdentifier is the identifier of the header, numbered if it is empty or already used
+func uniqueIdentifier(header string, index int, used map[string]bool) string {
+	name := identifier(header)
+	if name == "" {
+		name = "Column" + strconv.Itoa(index)
+	}
+
+	unique := name
+	for n := 2; used[unique]; n++ {
+		unique = name + strconv.Itoa(n)
+	}
+	used[unique] = true
+
+	return unique
+}
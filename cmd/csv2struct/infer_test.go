@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KalleDK/go-csv/cmd/csv2struct/testdata/padded"
+	"github.com/KalleDK/go-csv/cmd/csv2struct/testdata/partners"
+	"github.com/KalleDK/go-csv/csv"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		want     string
+		optional bool
+	}{
+		{name: "int", data: "A\n1\n-2\n", want: "Int"},
+		{name: "leading zero", data: "A\n1\n007\n", want: "String"},
+		{name: "float", data: "A\n1\n2.5\n", want: "Float"},
+		{name: "bool", data: "A\ntrue\nfalse\n", want: "Bool"},
+		{name: "bool digits", data: "A\n1\n0\n", want: "Int"},
+		{name: "rfc3339", data: "A\n2021-03-04T10:00:00Z\n", want: "Time"},
+		{name: "date", data: "A\n2021-03-04\n", want: "Date"},
+		{name: "date time", data: "A\n2021-03-04 10:00:00\n", want: "DateTime"},
+		{name: "us date", data: "A\n12/31/2021\n", want: "USDate"},
+		{name: "eu date", data: "A\n31.12.2021\n", want: "EUDate"},
+		{name: "mixed", data: "A\n1\nBob\n", want: "String"},
+		{name: "blank", data: "A\n1\n\"\"\n", want: "Int", optional: true},
+		{name: "all blank", data: "A,B\n,1\n", want: "String", optional: true},
+		{name: "short record", data: "B,A\n1\n", want: "String", optional: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := csv.NewReader(strings.NewReader(tt.data), &csv.Options{FieldsPerRecord: -1})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			columns, err := infer(reader, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got *column
+			for i := range columns {
+				if columns[i].Header == "A" {
+					got = &columns[i]
+				}
+			}
+
+			if got.Converter.Name != tt.want || got.IsOptional != tt.optional {
+				t.Errorf("infer() = %v optional %v, want %v optional %v", got.Converter.Name, got.IsOptional, tt.want, tt.optional)
+			}
+		})
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Name", "Name"},
+		{"full name", "FullName"},
+		{"e-mail", "EMail"},
+		{"partnerID", "PartnerID"},
+		{"2nd Contact", "Column2ndContact"},
+		{"Straße", "Straße"},
+		{"%", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := identifier(tt.header); got != tt.want {
+				t.Errorf("identifier(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		pkg      string
+		typeName string
+	}{
+		{"partners", "Partner"},
+		{"padded", "Padded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			file, err := os.Open(filepath.Join("testdata", tt.pkg+".csv"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			reader, err := csv.NewReader(file, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			columns, err := infer(reader, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := generate(columns, tt.pkg, tt.typeName, tt.pkg+".csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", tt.pkg, tt.pkg+".go")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != string(want) {
+				t.Errorf("generated code differs from %v, run go test -update\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestGenerate_Unmarshal(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "partners.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []partners.Partner{}
+	if err := csv.Unmarshal(&got, nil, data); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if len(got) != 3 || got[1].Rating == nil || *got[1].Rating != 3 || got[2].Rating != nil || got[1].LastOrder != nil {
+		t.Errorf("Unmarshal() = %+v", got)
+	}
+
+	out, err := csv.Marshal(got, nil)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(out) != string(data) {
+		t.Errorf("Marshal() = %s, want %s", out, data)
+	}
+}
+
+// The padded cells are inferred as numbers and booleans, so the generated methods must read them as well,
+// and the header with a comma is named by a quoted tag
+func TestGenerate_UnmarshalPadded(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "padded.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []padded.Padded{}
+	if err := csv.Unmarshal(&got, nil, data); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if len(got) != 3 || got[0].Count == nil || *got[0].Count != 12 || got[0].Active == nil || !*got[0].Active ||
+		got[0].Score == nil || *got[0].Score != 2.5 || got[0].Total != 7 || got[2].Count != nil || got[2].AmountEUR != 3 {
+		t.Errorf("Unmarshal() = %+v", got)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Csv2struct prints a struct for decoding a csv file with the csv package.
+
+Usage:
+
+	csv2struct [flags] [file]
+
+The columns are named by the header row and their types are inferred from the cells: bool, int, float64,
+time.Time or string. Columns without blank cells are required. Columns with blank cells are optional, and
+unless they are strings their fields are pointers, read by generated methods that leave blank cells nil.
+Times in RFC 3339 are read by time.Time, other layouts get generated methods.
+
+The reader flags match the fields of csv.Options. Without a file the standard input is read.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+var (
+	typeName   = flag.String("type", "Record", "name of the struct")
+	pkgName    = flag.String("package", "main", "name of the package")
+	output     = flag.String("output", "", "output file name; default standard output")
+	limit      = flag.Int("limit", 0, "number of records to infer the types from; 0 reads all")
+	headers    = flag.String("headers", "", "comma-separated list of headers, instead of the first record")
+	noHeader   = flag.Bool("noheader", false, "the first record is data, the columns are named by their index")
+	sniff      = flag.Bool("sniff", false, "guess comma, comment, lazyquotes and noheader from the input")
+	comma      = flag.String("comma", ",", "field delimiter")
+	comment    = flag.String("comment", "", "comment character")
+	fields     = flag.Int("fields", 0, "number of fields per record; 0 uses the first record, negative allows any")
+	lazyQuotes = flag.Bool("lazyquotes", false, "allow quotes in unquoted fields and non-doubled quotes in quoted fields")
+	trim       = flag.Bool("trim", false, "ignore leading white space in fields")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of csv2struct:\n")
+	fmt.Fprintf(os.Stderr, "\tcsv2struct [flags] [file]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("csv2struct: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	options, err := readerOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var input io.Reader = os.Stdin
+	source := "stdin"
+	if flag.NArg() > 0 {
+		file, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+		source = filepath.Base(flag.Arg(0))
+	}
+
+	reader, err := csv.NewReader(input, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	columns, err := infer(reader, *limit)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(columns, *pkgName, *typeName, source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readerOptions collects the reader flags into csv.Options
+func readerOptions() (*csv.Options, error) {
+	options := &csv.Options{
+		NoHeader:         *noHeader,
+		Sniff:            *sniff,
+		FieldsPerRecord:  *fields,
+		LazyQuotes:       *lazyQuotes,
+		TrimLeadingSpace: *trim,
+	}
+
+	if *headers != "" {
+		options.Headers = strings.Split(*headers, ",")
+	}
+
+	var err error
+	if options.Comma, err = flagRune("comma", *comma); err != nil {
+		return nil, err
+	}
+	if options.Comment, err = flagRune("comment", *comment); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// flagRune reads a single character flag, "\t" is accepted for tab
+func flagRune(name string, value string) (rune, error) {
+	if value == `\t` {
+		return '\t', nil
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) {
+		return 0, fmt.Errorf("-%v must be a single character, got %q", name, value)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,84 @@
+package padded
+
+import (
+	"strconv"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+// Padded is a record of padded.csv, generated by csv2struct
+type Padded struct {
+	Count     *int     `csv:"Count,unmarshal=UnmarshalOptionalInt,marshal=MarshalOptionalInt"`
+	Active    *bool    `csv:"Active,unmarshal=UnmarshalOptionalBool,marshal=MarshalOptionalBool"`
+	Score     *float64 `csv:"Score,unmarshal=UnmarshalOptionalFloat,marshal=MarshalOptionalFloat"`
+	Total     int      `csv:"Total,required"`
+	AmountEUR float64  `csv:"'Amount, EUR',required"`
+}
+
+// UnmarshalOptionalInt leaves the field nil for blank cells
+func (*Padded) UnmarshalOptionalInt(v **int, text []byte) error {
+	if len(text) == 0 {
+		*v = nil
+		return nil
+	}
+	parsed, err := csv.ParseInt(string(text), 0)
+	if err != nil {
+		return err
+	}
+	value := int(parsed)
+	*v = &value
+	return nil
+}
+
+// MarshalOptionalInt writes a blank cell for nil
+func (*Padded) MarshalOptionalInt(v **int) ([]byte, error) {
+	if *v == nil {
+		return nil, nil
+	}
+	return []byte(strconv.Itoa(**v)), nil
+}
+
+// UnmarshalOptionalBool leaves the field nil for blank cells
+func (*Padded) UnmarshalOptionalBool(v **bool, text []byte) error {
+	if len(text) == 0 {
+		*v = nil
+		return nil
+	}
+	value, err := csv.ParseBool(string(text))
+	if err != nil {
+		return err
+	}
+	*v = &value
+	return nil
+}
+
+// MarshalOptionalBool writes a blank cell for nil
+func (*Padded) MarshalOptionalBool(v **bool) ([]byte, error) {
+	if *v == nil {
+		return nil, nil
+	}
+	return []byte(strconv.FormatBool(**v)), nil
+}
+
+// UnmarshalOptionalFloat leaves the field nil for blank cells
+func (*Padded) UnmarshalOptionalFloat(v **float64, text []byte) error {
+	if len(text) == 0 {
+		*v = nil
+		return nil
+	}
+	value, err := csv.ParseFloat(string(text), 64)
+	if err != nil {
+		return err
+	}
+	*v = &value
+	return nil
+}
+
+// MarshalOptionalFloat writes a blank cell for nil
+func (*Padded) MarshalOptionalFloat(v **float64) ([]byte, error) {
+	if *v == nil {
+		return nil, nil
+	}
+	text, err := csv.FormatFloat(**v, 64)
+	return []byte(text), err
+}
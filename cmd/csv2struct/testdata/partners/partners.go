@@ -0,0 +1,82 @@
+package partners
+
+import (
+	"time"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+// Partner is a record of partners.csv, generated by csv2struct
+type Partner struct {
+	PartnerID        int        `csv:"Partner ID,required"`
+	FullName         string     `csv:"Full Name,required"`
+	Active           bool       `csv:"Active,required"`
+	Rating           *float64   `csv:"Rating,unmarshal=UnmarshalOptionalFloat,marshal=MarshalOptionalFloat"`
+	SignedUp         time.Time  `csv:"Signed Up,required"`
+	LastOrder        *time.Time `csv:"Last Order,unmarshal=UnmarshalOptionalDateTime,marshal=MarshalOptionalDateTime"`
+	Renewal          time.Time  `csv:"Renewal,required,unmarshal=UnmarshalDate,marshal=MarshalDate"`
+	Notes            string     `csv:"Notes"`
+	Column2ndContact string     `csv:"2nd Contact"`
+	EMail            string     `csv:"e-mail,required"`
+	EMail2           string     `csv:"E Mail,required"`
+}
+
+// UnmarshalOptionalFloat leaves the field nil for blank cells
+func (*Partner) UnmarshalOptionalFloat(v **float64, text []byte) error {
+	if len(text) == 0 {
+		*v = nil
+		return nil
+	}
+	value, err := csv.ParseFloat(string(text), 64)
+	if err != nil {
+		return err
+	}
+	*v = &value
+	return nil
+}
+
+// MarshalOptionalFloat writes a blank cell for nil
+func (*Partner) MarshalOptionalFloat(v **float64) ([]byte, error) {
+	if *v == nil {
+		return nil, nil
+	}
+	text, err := csv.FormatFloat(**v, 64)
+	return []byte(text), err
+}
+
+// UnmarshalOptionalDateTime leaves the field nil for blank cells
+func (*Partner) UnmarshalOptionalDateTime(v **time.Time, text []byte) error {
+	if len(text) == 0 {
+		*v = nil
+		return nil
+	}
+	value, err := time.Parse("2006-01-02 15:04:05", string(text))
+	if err != nil {
+		return err
+	}
+	*v = &value
+	return nil
+}
+
+// MarshalOptionalDateTime writes a blank cell for nil
+func (*Partner) MarshalOptionalDateTime(v **time.Time) ([]byte, error) {
+	if *v == nil {
+		return nil, nil
+	}
+	return []byte((**v).Format("2006-01-02 15:04:05")), nil
+}
+
+// UnmarshalDate reads the layout time.Time can't read on its own
+func (*Partner) UnmarshalDate(v *time.Time, text []byte) error {
+	value, err := time.Parse("2006-01-02", string(text))
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
+
+// MarshalDate writes the layout read by UnmarshalDate
+func (*Partner) MarshalDate(v *time.Time) ([]byte, error) {
+	return []byte((*v).Format("2006-01-02")), nil
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/KalleDK/go-csv/internal/tag"
+)
+
+const (
+	tagKey     = "csv"
+	csvPackage = "github.com/KalleDK/go-csv/csv"
+)
+
+// Options of the tag that only matter to the fixed width reader, they are handled by the Record
+var ignoredOptions = map[string]bool{
+	"fixed":   true,
+	"pad":     true,
+	"align":   true,
+	"keeppad": true,
+}
+
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string
+	buf     bytes.Buffer
+}
+
+/*
+generate parses the package in dir, leaving out the file named skip, and returns the formatted source
+with the DecodeCSV and EncodeCSV methods of the named types.
+*/
+func generate(dir string, typeNames []string, skip string) ([]byte, error) {
+	pkg, err := loadPackage(dir, skip)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{pkg: pkg, imports: map[string]string{csvPackage: "csv"}}
+	for _, name := range typeNames {
+		if err := g.generateType(strings.TrimSpace(name)); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.format(typeNames)
+}
+
+func loadPackage(dir string, skip string) (*types.Package, error) {
+	fset := token.NewFileSet()
+	filter := func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != skip
+	}
+
+	pkgs, err := parser.ParseDir(fset, dir, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("want one package in %v, found %v", dir, len(pkgs))
+	}
+
+	var files []*ast.File
+	var name string
+	for pkgName, pkg := range pkgs {
+		name = pkgName
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+
+	// The package might not compile yet because it uses the methods we are about to generate
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := config.Check(name, fset, files, nil)
+
+	return pkg, nil
+}
+
+func (g *generator) generateType(name string) error {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return fmt.Errorf("type %v not found in package %v", name, g.pkg.Name())
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return fmt.Errorf("type %v is an alias", name)
+	}
+
+	structType, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return fmt.Errorf("can't generate for %v, want a struct", name)
+	}
+
+	fields := make([]fieldInfo, 0, structType.NumFields())
+	for i := 0; i < structType.NumFields(); i++ {
+		field, err := getFieldInfo(structType.Field(i), structType.Tag(i))
+		if err != nil {
+			return fmt.Errorf("%v: %v", name, err)
+		}
+		fields = append(fields, field)
+	}
+
+	g.printf("// DecodeCSV implements csv.RecordUnmarshaler\n")
+	g.printf("func (v *%s) DecodeCSV(row *csv.Record) error {\n", name)
+	for _, field := range fields {
+		if err := g.generateDecode(named, field); err != nil {
+			return fmt.Errorf("%v: %v", name, err)
+		}
+	}
+	g.printf("return nil\n}\n\n")
+
+	g.printf("// EncodeCSV implements csv.RecordMarshaler\n")
+	g.printf("func (v *%s) EncodeCSV(row *csv.Record) error {\n", name)
+	for _, field := range fields {
+		if err := g.generateEncode(named, field); err != nil {
+			return fmt.Errorf("%v: %v", name, err)
+		}
+	}
+	g.printf("return nil\n}\n\n")
+
+	return nil
+}
+
+func (g *generator) generateDecode(parent *types.Named, field fieldInfo) error {
+	g.printf("if text, found, err := row.Lookup(%q, %v); err != nil {\n", field.Name, !field.IsOptional)
+	g.printf("return err\n")
+	g.printf("} else if found {\n")
+
+	target := "v." + field.Field
+	fieldType := field.Type
+
+	switch {
+	case field.Unmarshal != "":
+		if err := verifyMethod(parent, field.Unmarshal, unmarshalSignature(fieldType)); err != nil {
+			return err
+		}
+		g.printf("if err := v.%s(&%s, []byte(text)); err != nil {\nreturn err\n}\n", field.Unmarshal, target)
+
+	case types.Implements(types.NewPointer(fieldType), csvUnmarshaler):
+		g.printf("if err := %s.UnmarshalCSV([]byte(text)); err != nil {\nreturn err\n}\n", target)
+
+	case isBig(fieldType):
+		g.printf("if err := csv.UnmarshalField(&%s, []byte(text)); err != nil {\nreturn err\n}\n", target)
+
+	case types.Implements(types.NewPointer(fieldType), textUnmarshaler):
+		g.printf("if err := %s.UnmarshalText([]byte(text)); err != nil {\nreturn err\n}\n", target)
+
+	case isBasic(fieldType, types.IsString):
+		g.printf("%s = %s\n", target, g.convert(fieldType, "text", types.Typ[types.String]))
+
+	// The numbers are parsed by the csv package, the same way as by the reflection based decoder
+	case isBasic(fieldType, types.IsBoolean):
+		g.printf("b, err := csv.ParseBool(text)\nif err != nil {\nreturn err\n}\n")
+		g.printf("%s = %s\n", target, g.convert(fieldType, "b", types.Typ[types.Bool]))
+
+	case isBasic(fieldType, types.IsUnsigned):
+		g.printf("n, err := csv.ParseUint(text, %d)\nif err != nil {\nreturn err\n}\n", bitSize(fieldType))
+		g.printf("%s = %s\n", target, g.convert(fieldType, "n", types.Typ[types.Uint64]))
+
+	case isBasic(fieldType, types.IsInteger):
+		g.printf("n, err := csv.ParseInt(text, %d)\nif err != nil {\nreturn err\n}\n", bitSize(fieldType))
+		g.printf("%s = %s\n", target, g.convert(fieldType, "n", types.Typ[types.Int64]))
+
+	case isBasic(fieldType, types.IsFloat):
+		g.printf("f, err := csv.ParseFloat(text, %d)\nif err != nil {\nreturn err\n}\n", bitSize(fieldType))
+		g.printf("%s = %s\n", target, g.convert(fieldType, "f", types.Typ[types.Float64]))
+
+	default:
+		g.printf("if err := csv.UnmarshalField(&%s, []byte(text)); err != nil {\nreturn err\n}\n", target)
+	}
+
+	g.printf("}\n\n")
+	return nil
+}
+
+func (g *generator) generateEncode(parent *types.Named, field fieldInfo) error {
+	g.printf("if row.HasHeader(%q) {\n", field.Name)
+
+	source := "v." + field.Field
+	fieldType := field.Type
+
+	switch {
+	case field.Marshal != "":
+		if err := verifyMethod(parent, field.Marshal, marshalSignature(fieldType)); err != nil {
+			return err
+		}
+		g.printf("data, err := v.%s(&%s)\nif err != nil {\nreturn err\n}\n", field.Marshal, source)
+		g.printf("row.Set(%q, string(data))\n", field.Name)
+
+	case types.Implements(types.NewPointer(fieldType), csvMarshaler):
+		g.printf("data, err := %s.MarshalCSV()\nif err != nil {\nreturn err\n}\n", source)
+		g.printf("row.Set(%q, string(data))\n", field.Name)
+
+	case isBig(fieldType):
+		g.printf("data, err := csv.MarshalField(&%s)\nif err != nil {\nreturn err\n}\n", source)
+		g.printf("row.Set(%q, string(data))\n", field.Name)
+
+	case types.Implements(types.NewPointer(fieldType), textMarshaler):
+		g.printf("data, err := %s.MarshalText()\nif err != nil {\nreturn err\n}\n", source)
+		g.printf("row.Set(%q, string(data))\n", field.Name)
+
+	case isBasic(fieldType, types.IsString):
+		g.printf("row.Set(%q, %s)\n", field.Name, g.convert(types.Typ[types.String], source, fieldType))
+
+	case isBasic(fieldType, types.IsBoolean):
+		g.use("strconv")
+		g.printf("row.Set(%q, strconv.FormatBool(bool(%s)))\n", field.Name, source)
+
+	case isBasic(fieldType, types.IsUnsigned):
+		g.use("strconv")
+		g.printf("row.Set(%q, strconv.FormatUint(uint64(%s), 10))\n", field.Name, source)
+
+	case isBasic(fieldType, types.IsInteger):
+		g.use("strconv")
+		g.printf("row.Set(%q, strconv.FormatInt(int64(%s), 10))\n", field.Name, source)
+
+	case isBasic(fieldType, types.IsFloat):
+		g.printf("text, err := csv.FormatFloat(float64(%s), %d)\nif err != nil {\nreturn err\n}\n", source, bitSize(fieldType))
+		g.printf("row.Set(%q, text)\n", field.Name)
+
+	default:
+		g.printf("data, err := csv.MarshalField(&%s)\nif err != nil {\nreturn err\n}\n", source)
+		g.printf("row.Set(%q, string(data))\n", field.Name)
+	}
+
+	if !field.IsOptional {
+		g.use("fmt")
+		g.printf("} else {\nreturn fmt.Errorf(\"required field i missing in header %%v\", %q)\n", field.Name)
+	}
+
+	g.printf("}\n\n")
+	return nil
+}
+
+func (g *generator) format(typeNames []string) ([]byte, error) {
+	var src bytes.Buffer
+
+	fmt.Fprintf(&src, "// Code generated by csvgen -type %s; DO NOT EDIT.\n\n", strings.Join(typeNames, ","))
+	fmt.Fprintf(&src, "package %s\n\n", g.pkg.Name())
+
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// The standard library first, then the csv package
+	src.WriteString("import (\n")
+	for _, path := range paths {
+		if path != csvPackage {
+			fmt.Fprintf(&src, "%q\n", path)
+		}
+	}
+	fmt.Fprintf(&src, "\n%q\n", csvPackage)
+	src.WriteString(")\n\n")
+	src.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %v", err)
+	}
+
+	return formatted, nil
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) use(path string) {
+	g.imports[path] = path
+}
+
+// typeString writes a type the way it is spelled in the generated file, importing its package if needed
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg == g.pkg {
+			return ""
+		}
+		g.use(pkg.Path())
+		return pkg.Name()
+	})
+}
+
+// convert returns expr of type from converted to t, leaving out conversions that are not needed
+func (g *generator) convert(t types.Type, expr string, from types.Type) string {
+	if types.Identical(t, from) {
+		return expr
+	}
+	return g.typeString(t) + "(" + expr + ")"
+}
+
+type fieldInfo struct {
+	Field      string
+	Name       string
+	Unmarshal  string
+	Marshal    string
+	IsOptional bool
+	Type       types.Type
+}
+
+// getFieldInfo reads the tag the same way as the reflection based decoder in the csv package
+func getFieldInfo(field *types.Var, fieldTag string) (fieldInfo, error) {
+	if field.Type() == types.Typ[types.Invalid] {
+		return fieldInfo{}, fmt.Errorf("can't resolve the type of field %v", field.Name())
+	}
+
+	parsed, err := tag.Parse(reflect.StructTag(fieldTag).Get(tagKey))
+	if err != nil {
+		return fieldInfo{}, fmt.Errorf("invalid tag on field %v: %v", field.Name(), err)
+	}
+
+	info := fieldInfo{
+		Field:      field.Name(),
+		Name:       parsed.Name,
+		Unmarshal:  parsed.Unmarshal,
+		Marshal:    parsed.Marshal,
+		IsOptional: true,
+		Type:       field.Type(),
+	}
+	if info.Name == "" {
+		info.Name = field.Name()
+	}
+
+	for _, option := range parsed.Options {
+		switch {
+		case option.Key == "required":
+			info.IsOptional = false
+		case option.Key == "optional":
+			info.IsOptional = true
+		case !ignoredOptions[option.Key]:
+			return fieldInfo{}, fmt.Errorf("option %v on field %v is not supported by csvgen", option.Key, field.Name())
+		}
+	}
+
+	return info, nil
+}
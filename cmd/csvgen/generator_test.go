@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KalleDK/go-csv/cmd/csvgen/testdata/person"
+	"github.com/KalleDK/go-csv/csv"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("testdata", "person")
+	golden := filepath.Join(dir, "person_csv.go")
+
+	got, err := generate(dir, []string{"Person"}, filepath.Base(golden))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("generated code differs from %v, run go test -update\n%s", golden, got)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "not a struct",
+			src:  "type T int",
+			err:  "want a struct",
+		},
+		{
+			name: "unknown type",
+			src:  "type S struct{}",
+			err:  "type T not found",
+		},
+		{
+			name: "value method",
+			src:  "type T struct {\n\tA int `csv:\"a,Parse\"`\n}\n\nfunc (T) Parse(a *int, text []byte) error { return nil }",
+			err:  "can't be value method",
+		},
+		{
+			name: "missing method",
+			src:  "type T struct {\n\tA int `csv:\"a,Parse\"`\n}",
+			err:  "invalid method name Parse",
+		},
+		{
+			name: "wrong signature",
+			src:  "type T struct {\n\tA int `csv:\"a,,Format\"`\n}\n\nfunc (*T) Format(a *int) string { return \"\" }",
+			err:  "invalid method signature",
+		},
+		{
+			name: "unsupported option",
+			src:  "type T struct {\n\tA int `csv:\"a,,,,unknown=1\"`\n}",
+			err:  "option unknown on field A is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := "package p\n\n" + tt.src + "\n"
+			if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := generate(dir, []string{"T"}, "t_csv.go")
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("generate() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestGenerate_MatchesReflection(t *testing.T) {
+	const header = "name,age,Score,Active,id,level,Born,Timeout,nick,Tags,Member\n"
+	const born = "2024-01-02T03:04:05Z"
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"valid", header + "Bob,12,1.5,true,42,3," + born + ",1000,bobby,\"[\"\"a\"\",\"\"b\"\"]\",Y\n"},
+		{"white space", header + "Bob, 12 ,1e21, false,0,-3," + born + ",0,x,null,N\n"},
+		{"bool as number", header + "Bob,12,1.5,1,42,3," + born + ",0,x,null,Y\n"},
+		{"NaN", header + "Bob,12,NaN,true,42,3," + born + ",0,x,null,Y\n"},
+		{"int with fraction", header + "Bob,1.5,1,true,42,3," + born + ",0,x,null,Y\n"},
+		{"negative uint", header + "Bob,1,1,true,-1,3," + born + ",0,x,null,Y\n"},
+		{"uint overflow", header + "Bob,1,1,true,4294967296,3," + born + ",0,x,null,Y\n"},
+		{"blank int", header + "Bob,,1,true,1,3," + born + ",0,x,null,Y\n"},
+		{"invalid time", header + "Bob,1,1,true,1,3,yesterday,0,x,null,Y\n"},
+		{"invalid json", header + "Bob,1,1,true,1,3," + born + ",0,x,a,Y\n"},
+		{"missing required", "age\n1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var generated []person.Person
+			generatedErr := csv.Unmarshal(&generated, nil, []byte(tt.data))
+
+			var reflected []person.Reflected
+			reflectedErr := csv.Unmarshal(&reflected, nil, []byte(tt.data))
+
+			if fmt.Sprint(generatedErr) != fmt.Sprint(reflectedErr) {
+				t.Fatalf("generated error %v, reflection error %v", generatedErr, reflectedErr)
+			}
+			if generatedErr != nil {
+				return
+			}
+
+			converted := make([]person.Person, len(reflected))
+			for i, r := range reflected {
+				converted[i] = person.Person(r)
+			}
+			if !reflect.DeepEqual(generated, converted) {
+				t.Fatalf("generated %+v, reflection %+v", generated, converted)
+			}
+
+			generatedData, generatedErr := csv.Marshal(generated, nil)
+			reflectedData, reflectedErr := csv.Marshal(reflected, nil)
+			if fmt.Sprint(generatedErr) != fmt.Sprint(reflectedErr) || string(generatedData) != string(reflectedData) {
+				t.Errorf("generated %q (%v), reflection %q (%v)", generatedData, generatedErr, reflectedData, reflectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Csvgen generates reflection free DecodeCSV and EncodeCSV methods for structs with csv tags.
+
+Usage:
+
+	//go:generate csvgen -type Person,Order
+
+	csvgen [-output file] -type T[,T...] [directory]
+
+The generated methods implement csv.RecordUnmarshaler and csv.RecordMarshaler, and are used by the Decoder,
+the TypedDecoder and the Encoder instead of reflection. The tags follow the same rules as the reflection based
+decoder: the header name, the unmarshal and marshal methods, required, and csv.Unmarshaler and csv.Marshaler
+before encoding.TextUnmarshaler and encoding.TextMarshaler. Booleans, numbers and the other types are converted
+with csv.ParseInt and the other functions the reflection based decoder uses, so the results don't depend on
+whether the methods were generated.
+
+The generated methods know nothing of the types and enums registered with a Decoder or an Encoder, or of
+Options.Infer. Once any of those are set the Decoder and the Encoder use reflection for all structs instead.
+
+By default the output is written to <type>_csv.go, in lower case, next to the package source.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names; must be set")
+	output    = flag.String("output", "", "output file name; default srcdir/<type>_csv.go")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of csvgen:\n")
+	fmt.Fprintf(os.Stderr, "\tcsvgen [-output file] -type T[,T...] [directory]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("csvgen: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	types := strings.Split(*typeNames, ",")
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(types[0])+"_csv.go")
+	}
+
+	src, err := generate(dir, types, filepath.Base(outputName))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(outputName, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+package person
+
+import (
+	"strings"
+	"time"
+)
+
+type Level int
+
+type Person struct {
+	Name     string `csv:"name,,,required"`
+	Age      int    `csv:"age"`
+	Score    float64
+	Active   bool
+	ID       uint32 `csv:"id,,,,fixed=0:5,pad=0,align=right"`
+	Level    Level  `csv:"level"`
+	Born     time.Time
+	Timeout  time.Duration
+	Nickname string `csv:"nick,UnmarshalNick,MarshalNick"`
+	Tags     []string
+	Member   Answer
+}
+
+// Answer is Y or N in csv, and yes or no as text
+type Answer bool
+
+func (a *Answer) UnmarshalCSV(text []byte) error {
+	*a = string(text) == "Y"
+	return nil
+}
+
+func (a *Answer) MarshalCSV() ([]byte, error) {
+	if *a {
+		return []byte("Y"), nil
+	}
+	return []byte("N"), nil
+}
+
+func (a *Answer) UnmarshalText(text []byte) error {
+	*a = string(text) == "yes"
+	return nil
+}
+
+func (a *Answer) MarshalText() ([]byte, error) {
+	if *a {
+		return []byte("yes"), nil
+	}
+	return []byte("no"), nil
+}
+
+func (*Person) UnmarshalNick(nick *string, text []byte) error {
+	*nick = strings.ToUpper(string(text))
+	return nil
+}
+
+func (*Person) MarshalNick(nick *string) ([]byte, error) {
+	return []byte(strings.ToLower(*nick)), nil
+}
@@ -0,0 +1,185 @@
+// Code generated by csvgen -type Person; DO NOT EDIT.
+
+package person
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+// DecodeCSV implements csv.RecordUnmarshaler
+func (v *Person) DecodeCSV(row *csv.Record) error {
+	if text, found, err := row.Lookup("name", true); err != nil {
+		return err
+	} else if found {
+		v.Name = text
+	}
+
+	if text, found, err := row.Lookup("age", false); err != nil {
+		return err
+	} else if found {
+		n, err := csv.ParseInt(text, 0)
+		if err != nil {
+			return err
+		}
+		v.Age = int(n)
+	}
+
+	if text, found, err := row.Lookup("Score", false); err != nil {
+		return err
+	} else if found {
+		f, err := csv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		v.Score = f
+	}
+
+	if text, found, err := row.Lookup("Active", false); err != nil {
+		return err
+	} else if found {
+		b, err := csv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.Active = b
+	}
+
+	if text, found, err := row.Lookup("id", false); err != nil {
+		return err
+	} else if found {
+		n, err := csv.ParseUint(text, 32)
+		if err != nil {
+			return err
+		}
+		v.ID = uint32(n)
+	}
+
+	if text, found, err := row.Lookup("level", false); err != nil {
+		return err
+	} else if found {
+		n, err := csv.ParseInt(text, 0)
+		if err != nil {
+			return err
+		}
+		v.Level = Level(n)
+	}
+
+	if text, found, err := row.Lookup("Born", false); err != nil {
+		return err
+	} else if found {
+		if err := v.Born.UnmarshalText([]byte(text)); err != nil {
+			return err
+		}
+	}
+
+	if text, found, err := row.Lookup("Timeout", false); err != nil {
+		return err
+	} else if found {
+		n, err := csv.ParseInt(text, 64)
+		if err != nil {
+			return err
+		}
+		v.Timeout = time.Duration(n)
+	}
+
+	if text, found, err := row.Lookup("nick", false); err != nil {
+		return err
+	} else if found {
+		if err := v.UnmarshalNick(&v.Nickname, []byte(text)); err != nil {
+			return err
+		}
+	}
+
+	if text, found, err := row.Lookup("Tags", false); err != nil {
+		return err
+	} else if found {
+		if err := csv.UnmarshalField(&v.Tags, []byte(text)); err != nil {
+			return err
+		}
+	}
+
+	if text, found, err := row.Lookup("Member", false); err != nil {
+		return err
+	} else if found {
+		if err := v.Member.UnmarshalCSV([]byte(text)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EncodeCSV implements csv.RecordMarshaler
+func (v *Person) EncodeCSV(row *csv.Record) error {
+	if row.HasHeader("name") {
+		row.Set("name", v.Name)
+	} else {
+		return fmt.Errorf("required field i missing in header %v", "name")
+	}
+
+	if row.HasHeader("age") {
+		row.Set("age", strconv.FormatInt(int64(v.Age), 10))
+	}
+
+	if row.HasHeader("Score") {
+		text, err := csv.FormatFloat(float64(v.Score), 64)
+		if err != nil {
+			return err
+		}
+		row.Set("Score", text)
+	}
+
+	if row.HasHeader("Active") {
+		row.Set("Active", strconv.FormatBool(bool(v.Active)))
+	}
+
+	if row.HasHeader("id") {
+		row.Set("id", strconv.FormatUint(uint64(v.ID), 10))
+	}
+
+	if row.HasHeader("level") {
+		row.Set("level", strconv.FormatInt(int64(v.Level), 10))
+	}
+
+	if row.HasHeader("Born") {
+		data, err := v.Born.MarshalText()
+		if err != nil {
+			return err
+		}
+		row.Set("Born", string(data))
+	}
+
+	if row.HasHeader("Timeout") {
+		row.Set("Timeout", strconv.FormatInt(int64(v.Timeout), 10))
+	}
+
+	if row.HasHeader("nick") {
+		data, err := v.MarshalNick(&v.Nickname)
+		if err != nil {
+			return err
+		}
+		row.Set("nick", string(data))
+	}
+
+	if row.HasHeader("Tags") {
+		data, err := csv.MarshalField(&v.Tags)
+		if err != nil {
+			return err
+		}
+		row.Set("Tags", string(data))
+	}
+
+	if row.HasHeader("Member") {
+		data, err := v.Member.MarshalCSV()
+		if err != nil {
+			return err
+		}
+		row.Set("Member", string(data))
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package person
+
+// Reflected has the fields and tags of Person without the generated methods, so it is decoded by reflection
+type Reflected Person
+
+func (*Reflected) UnmarshalNick(nick *string, text []byte) error {
+	return (*Person)(nil).UnmarshalNick(nick, text)
+}
+
+func (*Reflected) MarshalNick(nick *string) ([]byte, error) {
+	return (*Person)(nil).MarshalNick(nick)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+)
+
+var errorType = types.Universe.Lookup("error").Type()
+
+var bytesliceType = types.NewSlice(types.Typ[types.Byte])
+
+var csvUnmarshaler = newInterface("UnmarshalCSV", signature([]types.Type{bytesliceType}, []types.Type{errorType}))
+
+var csvMarshaler = newInterface("MarshalCSV", signature(nil, []types.Type{bytesliceType, errorType}))
+
+var textUnmarshaler = newInterface("UnmarshalText", signature([]types.Type{bytesliceType}, []types.Type{errorType}))
+
+var textMarshaler = newInterface("MarshalText", signature(nil, []types.Type{bytesliceType, errorType}))
+
+func newInterface(name string, sig *types.Signature) *types.Interface {
+	method := types.NewFunc(0, nil, name, sig)
+	return types.NewInterfaceType([]*types.Func{method}, nil).Complete()
+}
+
+func signature(in []types.Type, out []types.Type) *types.Signature {
+	return types.NewSignatureType(nil, nil, nil, tuple(in), tuple(out), false)
+}
+
+func tuple(list []types.Type) *types.Tuple {
+	vars := make([]*types.Var, len(list))
+	for i, t := range list {
+		vars[i] = types.NewParam(0, nil, "", t)
+	}
+	return types.NewTuple(vars...)
+}
+
+// unmarshalSignature is the signature of a tag unmarshal method, func(*T, []byte) error
+func unmarshalSignature(fieldType types.Type) *types.Signature {
+	return signature([]types.Type{types.NewPointer(fieldType), bytesliceType}, []types.Type{errorType})
+}
+
+// marshalSignature is the signature of a tag marshal method, func(*T) ([]byte, error)
+func marshalSignature(fieldType types.Type) *types.Signature {
+	return signature([]types.Type{types.NewPointer(fieldType)}, []types.Type{bytesliceType, errorType})
+}
+
+// verifyMethod checks a tag method by the same rules as the reflection based decoder
+func verifyMethod(parent *types.Named, name string, want *types.Signature) error {
+	valueMethods := types.NewMethodSet(parent)
+	if selection := valueMethods.Lookup(parent.Obj().Pkg(), name); selection != nil {
+		return fmt.Errorf("invalid method %v can't be value method", name)
+	}
+
+	pointerMethods := types.NewMethodSet(types.NewPointer(parent))
+	selection := pointerMethods.Lookup(parent.Obj().Pkg(), name)
+	if selection == nil {
+		return fmt.Errorf("invalid method name %v", name)
+	}
+
+	// Receivers are ignored when comparing signatures
+	if got := selection.Type().(*types.Signature); !types.Identical(got, want) {
+		return fmt.Errorf("invalid method signature %v want %v", got, want)
+	}
+
+	return nil
+}
+
+// isBig tells if t is one of the math/big numbers, or a pointer to one, which the csv package reads natively
+func isBig(t types.Type) bool {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "math/big" {
+		return false
+	}
+
+	switch named.Obj().Name() {
+	case "Int", "Float", "Rat":
+		return true
+	}
+	return false
+}
+
+func isBasic(t types.Type, info types.BasicInfo) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&info != 0
+}
+
+// bitSize is the size given to strconv, 0 for int and uint
+func bitSize(t types.Type) int {
+	switch t.Underlying().(*types.Basic).Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	case types.Int64, types.Uint64, types.Uintptr, types.Float64:
+		return 64
+	}
+	return 0
+}
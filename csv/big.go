@@ -0,0 +1,114 @@
+package csv
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+var bigFloatType = reflect.TypeOf(big.Float{})
+
+var bigRatType = reflect.TypeOf(big.Rat{})
+
+// bigUnmarshal returns the conversion of a math/big type or a pointer to one, ok is false for other types.
+// Numbers are read in base 10, and a blank cell is zero, or nil for a pointer.
+func bigUnmarshal(t reflect.Type) (unmarshaller nativeUnmarshaller, ok bool) {
+	pointer := t.Kind() == reflect.Ptr
+	if pointer {
+		t = t.Elem()
+	}
+
+	var parse func(v interface{}, text string) bool
+	switch t {
+	case bigIntType:
+		parse = func(v interface{}, text string) bool {
+			_, ok := v.(*big.Int).SetString(text, 10)
+			return ok
+		}
+	case bigFloatType:
+		parse = func(v interface{}, text string) bool {
+			f := v.(*big.Float)
+			// Enough bits for the digits, so long amounts keep their precision
+			if prec := uint(len(text)) * 4; prec > 64 {
+				f.SetPrec(prec)
+			}
+			_, _, err := f.Parse(text, 10)
+			return err == nil
+		}
+	case bigRatType:
+		parse = func(v interface{}, text string) bool {
+			_, ok := v.(*big.Rat).SetString(text)
+			return ok
+		}
+	default:
+		return nil, false
+	}
+
+	return func(v interface{}, text []byte) error {
+		target := reflect.ValueOf(v).Elem()
+		if pointer {
+			if len(text) == 0 {
+				target.Set(reflect.Zero(target.Type()))
+				return nil
+			}
+			target.Set(reflect.New(t))
+			target = target.Elem()
+		}
+
+		if len(text) == 0 {
+			target.Set(reflect.Zero(t))
+			return nil
+		}
+
+		if !parse(target.Addr().Interface(), string(text)) {
+			return fmt.Errorf("invalid %v %q", t, text)
+		}
+		return nil
+	}, true
+}
+
+// bigMarshal returns the conversion of a math/big type or a pointer to one, ok is false for other types.
+// Floats are written without exponent, and rationals as exact decimals if they have one, like 12.50 as 12.5.
+// Other rationals are written as a fraction, like 1/3. A nil pointer is written as a blank cell.
+func bigMarshal(t reflect.Type) (marshaller nativeMarshaller, ok bool) {
+	pointer := t.Kind() == reflect.Ptr
+	if pointer {
+		t = t.Elem()
+	}
+
+	var format func(v interface{}) string
+	switch t {
+	case bigIntType:
+		format = func(v interface{}) string {
+			return v.(*big.Int).Text(10)
+		}
+	case bigFloatType:
+		format = func(v interface{}) string {
+			return v.(*big.Float).Text('f', -1)
+		}
+	case bigRatType:
+		format = func(v interface{}) string {
+			r := v.(*big.Rat)
+			if decimals, exact := r.FloatPrec(); exact {
+				return r.FloatString(decimals)
+			}
+			return r.RatString()
+		}
+	default:
+		return nil, false
+	}
+
+	return func(v interface{}) ([]byte, error) {
+		value := reflect.ValueOf(v).Elem()
+		if pointer {
+			if value.IsNil() {
+				return []byte{}, nil
+			}
+			value = value.Elem()
+		}
+
+		return []byte(format(value.Addr().Interface())), nil
+	}, true
+}
@@ -0,0 +1,77 @@
+package csv
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+type ledgerRow struct {
+	Account string
+	Units   big.Int
+	Rate    *big.Float
+	Amount  *big.Rat
+}
+
+func TestUnmarshal_Big(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"integers", "Account,Units,Rate,Amount\nA,123456789012345678901234567890,1.5,10\n", "Account,Units,Rate,Amount\nA,123456789012345678901234567890,1.5,10\n", false},
+		{"decimals", "Account,Units,Rate,Amount\nA,1,0.1,12345678901234567.89\n", "Account,Units,Rate,Amount\nA,1,0.1,12345678901234567.89\n", false},
+		{"fraction", "Account,Units,Rate,Amount\nA,1,2,1/3\n", "Account,Units,Rate,Amount\nA,1,2,1/3\n", false},
+		{"trailing zeros", "Account,Units,Rate,Amount\nA,1,2,12.50\n", "Account,Units,Rate,Amount\nA,1,2,12.5\n", false},
+		{"blank", "Account,Units,Rate,Amount\nA,,,\n", "Account,Units,Rate,Amount\nA,0,,\n", false},
+		{"invalid int", "Account,Units,Rate,Amount\nA,1.5,,\n", "", true},
+		{"invalid float", "Account,Units,Rate,Amount\nA,1,abc,\n", "", true},
+		{"invalid rat", "Account,Units,Rate,Amount\nA,1,,1//2\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []ledgerRow
+			err := Unmarshal(&rows, nil, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			data, err := Marshal(rows, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_RegisterType(t *testing.T) {
+	type row struct {
+		Name string
+		Code int
+	}
+
+	var rows []row
+	decoder, err := NewDecoder(strings.NewReader("Name,Code\na,x7\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder.RegisterType(0, func(v interface{}, text []byte) error {
+		*v.(*int) = len(text)
+		return nil
+	})
+	if err := decoder.Decode(&rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if rows[0].Code != 2 {
+		t.Errorf("got code %v, want 2", rows[0].Code)
+	}
+}
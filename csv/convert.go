@@ -0,0 +1,150 @@
+package csv
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+/*
+ParseBool, ParseInt, ParseUint and ParseFloat convert a cell the way the Decoder converts fields of those kinds.
+The cell is read as a JSON literal, so 1 is not a bool, NaN is not a float, and white space around the value is
+ignored. bitSize is the size of the field as for strconv, 0 for int and uint.
+
+The code generated by csvgen calls them, so generated and reflection based decoders agree.
+*/
+func ParseBool(text string) (bool, error) {
+	var b bool
+	err := json.Unmarshal([]byte(text), &b)
+	return b, err
+}
+
+// ParseInt converts a cell to an int of bitSize bits, see ParseBool
+func ParseInt(text string, bitSize int) (int64, error) {
+	var n int64
+	if err := json.Unmarshal([]byte(text), &n); err != nil {
+		return 0, err
+	}
+
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+	if bitSize < 64 && (n < -1<<(bitSize-1) || n > 1<<(bitSize-1)-1) {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: text, Err: strconv.ErrRange}
+	}
+	return n, nil
+}
+
+// ParseUint converts a cell to a uint of bitSize bits, see ParseBool
+func ParseUint(text string, bitSize int) (uint64, error) {
+	var n uint64
+	if err := json.Unmarshal([]byte(text), &n); err != nil {
+		return 0, err
+	}
+
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+	if bitSize < 64 && n > 1<<bitSize-1 {
+		return 0, &strconv.NumError{Func: "ParseUint", Num: text, Err: strconv.ErrRange}
+	}
+	return n, nil
+}
+
+// ParseFloat converts a cell to a float of bitSize bits, see ParseBool
+func ParseFloat(text string, bitSize int) (float64, error) {
+	var f float64
+	if err := json.Unmarshal([]byte(text), &f); err != nil {
+		return 0, err
+	}
+
+	if bitSize == 32 && math.Abs(f) > math.MaxFloat32 {
+		return 0, &strconv.NumError{Func: "ParseFloat", Num: text, Err: strconv.ErrRange}
+	}
+	return f, nil
+}
+
+/*
+FormatFloat writes a float field the way the Encoder does, like JSON, with an exponent for very large and
+very small numbers. NaN and infinities are errors.
+*/
+func FormatFloat(f float64, bitSize int) (string, error) {
+	var data []byte
+	var err error
+	if bitSize == 32 {
+		data, err = json.Marshal(float32(f))
+	} else {
+		data, err = json.Marshal(f)
+	}
+	return string(data), err
+}
+
+/*
+UnmarshalField converts text into the value v points to, the way the Decoder converts a cell of that type
+when the tag names no method. csvgen calls it for the types it has no conversion of its own for.
+*/
+func UnmarshalField(v interface{}, text []byte) error {
+	return nativeUnmarshal(reflect.TypeOf(v).Elem()).Unmarshal(v, text)
+}
+
+/*
+MarshalField returns the text of the value v points to, the way the Encoder writes a field of that type
+when the tag names no method. See UnmarshalField.
+*/
+func MarshalField(v interface{}) ([]byte, error) {
+	return nativeMarshal(reflect.TypeOf(v).Elem()).Marshal(v)
+}
+
+// nativeUnmarshalKind converts the basic kinds with the Parse functions, ok is false for other kinds
+func nativeUnmarshalKind(t reflect.Type) (unmarshaller nativeUnmarshaller, ok bool) {
+	switch t.Kind() {
+	case reflect.String:
+		return func(v interface{}, text []byte) error {
+			reflect.ValueOf(v).Elem().SetString(string(text))
+			return nil
+		}, true
+
+	case reflect.Bool:
+		return func(v interface{}, text []byte) error {
+			b, err := ParseBool(string(text))
+			if err != nil {
+				return err
+			}
+			reflect.ValueOf(v).Elem().SetBool(b)
+			return nil
+		}, true
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v interface{}, text []byte) error {
+			n, err := ParseInt(string(text), t.Bits())
+			if err != nil {
+				return err
+			}
+			reflect.ValueOf(v).Elem().SetInt(n)
+			return nil
+		}, true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v interface{}, text []byte) error {
+			n, err := ParseUint(string(text), t.Bits())
+			if err != nil {
+				return err
+			}
+			reflect.ValueOf(v).Elem().SetUint(n)
+			return nil
+		}, true
+
+	case reflect.Float32, reflect.Float64:
+		return func(v interface{}, text []byte) error {
+			f, err := ParseFloat(string(text), t.Bits())
+			if err != nil {
+				return err
+			}
+			reflect.ValueOf(v).Elem().SetFloat(f)
+			return nil
+		}, true
+	}
+
+	return nil, false
+}
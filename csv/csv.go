@@ -1,6 +1,7 @@
 package csv
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"reflect"
@@ -15,6 +16,16 @@ type Options struct {
 	// If Headers are nil, the first record is expected to be headers
 	Headers []string
 
+	// NoHeader tells that the first record is data and not headers.
+	// If Headers are nil as well, the columns are named by their
+	// zero-based index ("0", "1", ...).
+	NoHeader bool
+
+	// If Sniff is true, the start of the input is inspected to guess
+	// Comma, Comment, LazyQuotes and NoHeader, replacing the values given here.
+	// See Sniff for details.
+	Sniff bool
+
 	// Comma is the field delimiter.
 	// It is set to comma (',') by NewDecoder.
 	// Comma must be a valid rune and must not be \r, \n,
@@ -45,50 +56,450 @@ type Options struct {
 	// If TrimLeadingSpace is true, leading white space in a field is ignored.
 	// This is done even if the field delimiter, Comma, is white space.
 	TrimLeadingSpace bool
+
+	// If Append is true, Decode appends the records to the slice v points to
+	// instead of replacing its elements. Arrays and structs are always replaced.
+	Append bool
+
+	// Workers is the number of goroutines converting records into structs
+	// when decoding into a slice or an array. Records are still read one at a
+	// time and the result keeps the order of the input. If Workers is 0 or 1
+	// the records are converted by the calling goroutine.
+	// Structs with a detail field are always decoded by the calling goroutine.
+	Workers int
+
+	// Unsorted tells that the rows of a master/detail group may be spread over
+	// the input instead of following each other. All the records are then read
+	// before the first group is decoded, and the groups keep the order of their
+	// first rows. See Unmarshal for the key and detail tag options.
+	Unsorted bool
+
+	// Progress, if not nil, is called with the progress of the decoder every
+	// ProgressInterval records, and once more when the end of the input is reached.
+	Progress func(Progress)
+
+	// ProgressInterval is the number of records decoded between calls to Progress.
+	// It is set to 1000 if zero.
+	ProgressInterval int
+
+	// Columns, if not nil, is the layout of a fixed-width file.
+	// Each line is split into the given columns instead of delimited fields,
+	// and Comma, FieldsPerRecord, LazyQuotes and TrimLeadingSpace are ignored.
+	// FixedWidthColumns returns the layout declared in the tags of a struct.
+	Columns []FixedWidthColumn
+
+	// FileName is the name of the input. It is returned by Record.File and
+	// stored in the fields tagged meta=file.
+	FileName string
+
+	// Infer, if not nil, are the rules guessing the type of the cells read into
+	// interface{} fields, which are otherwise read as JSON. The cells of
+	// map[string]interface{} targets are always inferred, with DefaultInference
+	// if Infer is nil. See InferRule.
+	Infer []InferRule
+
+	// Transforms are named transforms for the transform tag option, added to
+	// the built in ones or replacing them. Unlike Decoder.RegisterTransform
+	// they are known before the first struct is checked, as needed by
+	// NewTypedDecoder. See Transform.
+	Transforms map[string]Transform
+
+	// Enums are registered with the Decoder or the Encoder when it is created,
+	// as with RegisterEnum. Like Transforms they are known before the first
+	// struct is checked, so defaults naming a value of an Enum can be used by
+	// NewTypedDecoder and MultiDecoder.Register.
+	Enums []*Enum
+
+	// Types are registered with the Decoder or the Encoder when it is created,
+	// as with RegisterType, see Enums.
+	Types []TypeConverter
+}
+
+/*
+TypeConverter holds the conversions of the type of Value for Options.Types. The Decoder uses Unmarshal and the
+Encoder Marshal, as given to Decoder.RegisterType and Encoder.RegisterType. A nil function is not registered.
+*/
+type TypeConverter struct {
+	Value     interface{}
+	Unmarshal UnmarshalFunc
+	Marshal   MarshalFunc
 }
 
 /*
 A Decoder reads and decodes CSV values from an input stream.
 */
 type Decoder struct {
-	headers headerMap
-	reader  csvReader
+	reader     *Reader
+	options    Options
+	decoders   map[reflect.Type]*recordDecoder
+	converters map[reflect.Type]objectUnmarshaler
+	transforms map[string]Transform
+	progress   progressTracker
+
+	// projection holds the fields used by the type being decoded, the others are not converted
+	projection columnProjection
+
+	// group reads the groups of the type being decoded, if it is a master with a detail field
+	group *groupReader
 }
 
 /*
-Decode reads the next CSV-encoded value from its input and stores it in the value pointed to by v.
+Decode reads the CSV-encoded records from its input and stores them in the value pointed to by v.
 
 See the documentation for Unmarshal for details about the conversion of CSV into a Go value.
 */
-func (d Decoder) Decode(v interface{}) error {
-	valueSlice := reflect.ValueOf(v).Elem()
+func (d *Decoder) Decode(v interface{}) error {
+	return d.DecodeContext(context.Background(), v)
+}
+
+/*
+DecodeContext works like Decode, but stops between records with the error of ctx when ctx is done.
+*/
+func (d *Decoder) DecodeContext(ctx context.Context, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+
+	target := value.Elem()
+
+	switch target.Kind() {
+	case reflect.Slice:
+		return d.decodeSlice(ctx, target)
+	case reflect.Array:
+		return d.decodeArray(ctx, target)
+	case reflect.Struct, reflect.Map:
+		return d.decodeSingle(ctx, target)
+	}
+
+	return fmt.Errorf("can't decode into %v, want a struct, or a slice or array of structs", target.Type())
+}
+
+func (d *Decoder) decodeSlice(ctx context.Context, target reflect.Value) error {
+	elemType := target.Type().Elem()
+	if err := d.prepareElement(elemType, "slice"); err != nil {
+		return err
+	}
+
+	slice := reflect.MakeSlice(target.Type(), 0, 0)
+	if d.options.Append {
+		slice = target
+	}
+
+	err := d.decodeRecords(ctx, elemType, func(elem reflect.Value) error {
+		slice = reflect.Append(slice, elem)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	target.Set(slice)
+
+	return nil
+}
+
+func (d *Decoder) decodeArray(ctx context.Context, target reflect.Value) error {
+	elemType := target.Type().Elem()
+	if err := d.prepareElement(elemType, "array"); err != nil {
+		return err
+	}
+
+	// Elements without a record are left as zero values
+	array := reflect.New(target.Type()).Elem()
+	i := 0
+
+	err := d.decodeRecords(ctx, elemType, func(elem reflect.Value) error {
+		if i >= array.Len() {
+			return fmt.Errorf("can't decode more than %v records into array %v", array.Len(), target.Type())
+		}
+		array.Index(i).Set(elem)
+		i++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	target.Set(array)
+
+	return nil
+}
+
+// decodeRecords decodes the remaining records into new elements of elemType and passes them to add in input order
+func (d *Decoder) decodeRecords(ctx context.Context, elemType reflect.Type, add func(reflect.Value) error) error {
+	if d.options.Workers > 1 && d.group == nil {
+		return d.decodeParallel(ctx, elemType, add)
+	}
+
+	for {
+		elem, err := d.nextElement(ctx, elemType)
+		if err == io.EOF {
+			d.progress.done()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := add(elem); err != nil {
+			return err
+		}
+	}
+}
 
-	decoder, err := newRecordDecoder(structType{Type: valueSlice.Type().Elem()}, d.headers)
+// nextElement decodes the next record, or the next group of records, into a new element of elemType
+func (d *Decoder) nextElement(ctx context.Context, elemType reflect.Type) (reflect.Value, error) {
+	if d.group != nil {
+		return d.group.next(ctx, elemType)
+	}
+
+	record, err := d.readRecord(ctx)
 	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	elem, err := d.newElement(elemType, record)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	d.progress.decoded(record.line, d.reader.InputOffset())
+	return elem, nil
+}
+
+func (d *Decoder) decodeSingle(ctx context.Context, target reflect.Value) error {
+	if err := d.prepareElement(target.Type(), target.Kind().String()); err != nil {
 		return err
 	}
 
-	slice := reflect.MakeSlice(valueSlice.Type(), 0, 0)
-	record, err := d.reader.Read()
-	for err == nil {
-		value := reflect.New(valueSlice.Type().Elem()).Elem()
-		err = decoder.Unmarshal(structRecord(value), record)
+	value, err := d.nextElement(ctx, target.Type())
+	if err == io.EOF {
+		return fmt.Errorf("can't decode into %v, there are no records", target.Type())
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.nextElement(ctx, target.Type()); err != io.EOF {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("can't decode more than one record into %v, use a slice", target.Type())
+	}
+	d.progress.done()
+
+	target.Set(value)
+
+	return nil
+}
+
+// prepareElement verifies that the element type is a struct, a pointer to one or a map, and creates its record decoder
+// up front, so invalid structs fail even without records
+func (d *Decoder) prepareElement(elemType reflect.Type, container string) error {
+	// Maps hold all the columns
+	if elemType.Kind() == reflect.Map {
+		d.projection = nil
+		d.group = nil
+		return verifyMap(elemType, container, d.reader.headers)
+	}
+
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("can't decode into %v element %v, want a struct or a pointer to a struct", container, elemType)
+	}
+
+	grouping, err := d.getGrouping(structType)
+	if err != nil {
+		return err
+	}
+
+	d.group = nil
+	if grouping != nil {
+		master, err := d.recordDecoder(structType)
 		if err != nil {
 			return err
 		}
-		slice = reflect.Append(slice, value)
-		record, err = d.reader.Read()
+
+		d.group = newGroupReader(d, grouping)
+		d.projection = grouping.projection(master)
+		return nil
+	}
+
+	if d.generated(structType) {
+		d.projection = nil
+		return nil
 	}
 
-	if err != io.EOF {
+	decoder, err := d.recordDecoder(structType)
+	if err != nil {
 		return err
 	}
 
-	valueSlice.Set(slice)
+	d.projection = decoder.projection()
+	return nil
+}
+
+// newElement returns a new element of the given type, a struct, a pointer to a struct or a map, holding the record
+func (d *Decoder) newElement(elemType reflect.Type, record *Record) (reflect.Value, error) {
+	if elemType.Kind() == reflect.Map {
+		return d.newMap(record), nil
+	}
+
+	if elemType.Kind() == reflect.Ptr {
+		elem := reflect.New(elemType.Elem())
+		return elem, d.unmarshalValue(elem.Elem(), record)
+	}
+
+	elem := reflect.New(elemType).Elem()
+	return elem, d.unmarshalValue(elem, record)
+}
+
+/*
+DecodeRecord stores the fields of record in the struct pointed to by v.
+
+The record is expected to be read from the Reader returned by d.Reader.
+*/
+func (d *Decoder) DecodeRecord(record *Record, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+
+	if value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("can't decode a record into %v, want a struct", value.Elem().Type())
+	}
 
+	return d.decodeValue(value.Elem(), record)
+}
+
+// readRecord reads the next record, unless ctx is done
+func (d *Decoder) readRecord(ctx context.Context) (*Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return d.reader.readRecord(d.projection)
+}
+
+// decodeValue stores the record in value, which must be an addressable struct, and counts it as decoded
+func (d *Decoder) decodeValue(value reflect.Value, record *Record) error {
+	if err := d.unmarshalValue(value, record); err != nil {
+		return err
+	}
+
+	d.progress.decoded(record.line, d.reader.InputOffset())
 	return nil
 }
 
+// unmarshalValue stores the record in value, which must be an addressable struct.
+// It is safe for concurrent use once the record decoder of the type is created.
+func (d *Decoder) unmarshalValue(value reflect.Value, record *Record) error {
+	if d.generated(value.Type()) {
+		return unmarshalStruct(nil, value, record)
+	}
+
+	decoder, err := d.recordDecoder(value.Type())
+	if err != nil {
+		return err
+	}
+
+	return unmarshalStruct(decoder, value, record)
+}
+
+/*
+generated tells if structs of type t are decoded by their DecodeCSV method. Generated decoders look up the fields
+themselves, without a record decoder, but know nothing of the converters registered with the Decoder or of
+Options.Infer, so the types fall back to reflection once the Decoder has any.
+*/
+func (d *Decoder) generated(t reflect.Type) bool {
+	return len(d.converters) == 0 && reflect.PtrTo(t).Implements(recordUnmarshalerType)
+}
+
+// unmarshalStruct stores the record in value with the decoder, or with the generated DecodeCSV method if it is nil
+func unmarshalStruct(decoder *recordDecoder, value reflect.Value, record *Record) error {
+	v := value.Addr().Interface()
+
+	var err error
+	if decoder == nil {
+		err = v.(RecordUnmarshaler).DecodeCSV(record)
+	} else {
+		err = decoder.UnmarshalRecord(structRecord(value), record)
+	}
+
+	if err != nil {
+		// Field errors don't know the line they are on
+		if recordErr, ok := err.(*RecordError); ok && recordErr.Line == 0 {
+			recordErr.Line = record.line
+		}
+		return err
+	}
+
+	return afterDecode(v, record)
+}
+
+/*
+Reader returns the Reader the decoder reads its records from.
+*/
+func (d *Decoder) Reader() *Reader {
+	return d.reader
+}
+
+func (d *Decoder) recordDecoder(t reflect.Type) (*recordDecoder, error) {
+	if decoder, found := d.decoders[t]; found {
+		return decoder, nil
+	}
+
+	decoder, err := newRecordDecoder(structType{Type: t}, d.reader.headerMap, d.converters, d.transforms)
+	if err != nil {
+		return nil, err
+	}
+
+	d.decoders[t] = decoder
+	return decoder, nil
+}
+
+/*
+RegisterEnum makes the decoder read fields of the type of enum by name, unless their tag names a method or an enum.
+*/
+func (d *Decoder) RegisterEnum(enum *Enum) {
+	d.register(enum.typ, enum)
+}
+
+/*
+RegisterType makes the decoder read fields of the type of v with unmarshal, unless their tag names a method or an enum.
+unmarshal is called with a pointer to the field, and the text of the cell.
+
+RegisterType is the way to plug in types the decoder doesn't know, like the decimals of a decimal library:
+
+	decoder.RegisterType(decimal.Decimal{}, func(v interface{}, text []byte) error {
+		d, err := decimal.NewFromString(string(text))
+		*v.(*decimal.Decimal) = d
+		return err
+	})
+*/
+func (d *Decoder) RegisterType(v interface{}, unmarshal UnmarshalFunc) {
+	d.register(reflect.TypeOf(v), unmarshal)
+}
+
+/*
+RegisterTransform adds a named transform for the transform tag option, or replaces a built in one. See Transform.
+*/
+func (d *Decoder) RegisterTransform(name string, transform Transform) {
+	d.transforms[name] = transform
+	d.decoders = map[reflect.Type]*recordDecoder{}
+}
+
+// register sets the converter of a type, and drops the record decoders made without it
+func (d *Decoder) register(t reflect.Type, converter objectUnmarshaler) {
+	d.converters[t] = converter
+	d.decoders = map[reflect.Type]*recordDecoder{}
+}
+
 /*
 NewDecoder returns a new decoder that reads from r.
 
@@ -97,27 +508,117 @@ The decoder introduces its own buffering and may read data from r beyond the CSV
 If headers is nil the headers are expected to be in the first csv record
 */
 func NewDecoder(r io.Reader, options *Options) (*Decoder, error) {
+	reader, err := NewReader(r, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDecoder(reader, options), nil
+}
+
+/*
+NewDecoderFromRecords returns a new decoder that decodes the records produced by r.
+
+Only options.Headers, options.NoHeader and options.FileName are used, the remaining options describe the CSV format and don't apply to a RecordReader.
+If options.Headers is nil the headers are expected to be in the first record
+*/
+func NewDecoderFromRecords(r RecordReader, options *Options) (*Decoder, error) {
 	if r == nil {
 		return nil, fmt.Errorf("reader can't be nil")
 	}
 
-	csvreader := newReader(r, options)
-
 	var headerlist []string
+	var noHeader bool
 	if options != nil {
 		headerlist = options.Headers
+		noHeader = options.NoHeader
 	}
 
-	headermap, err := getHeaders(csvreader, headerlist)
+	reader, err := newRecordReader(recordSource{r}, headerlist, noHeader, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Decoder{headermap, csvreader}, nil
+	return newDecoder(reader, options), nil
+}
+
+func newDecoder(reader *Reader, options *Options) *Decoder {
+	decoder := &Decoder{
+		reader:     reader,
+		decoders:   map[reflect.Type]*recordDecoder{},
+		converters: map[reflect.Type]objectUnmarshaler{},
+		transforms: map[string]Transform{},
+	}
+	for name, transform := range builtinTransforms {
+		decoder.transforms[name] = transform
+	}
+	if options != nil {
+		decoder.options = *options
+		decoder.progress = newProgressTracker(options)
+		if options.Infer != nil {
+			decoder.converters[interfaceType] = inference(options.Infer)
+		}
+		for name, transform := range options.Transforms {
+			decoder.transforms[name] = transform
+		}
+		for _, converter := range options.Types {
+			if converter.Unmarshal != nil {
+				decoder.converters[reflect.TypeOf(converter.Value)] = converter.Unmarshal
+			}
+		}
+		for _, enum := range options.Enums {
+			decoder.converters[enum.typ] = enum
+		}
+	}
+	return decoder
 }
 
 /*
 Unmarshal parses the CSV-encoded data and stores the result in the value pointed to by v. If v is nil or not a pointer, Unmarshal returns an InvalidUnmarshalError.
+
+v can point to
+  - a slice of structs, []T, or of pointers to structs, []*T, which is replaced by a slice holding all the records,
+    or extended with them if Options.Append is set
+  - an array, [N]T or [N]*T, which holds at most N records, remaining elements are set to zero values
+  - a struct, T, which holds the only record in the data
+  - a map[string]interface{}, as element or alone, which holds the cells of a record by header, with types
+    inferred by Options.Infer or DefaultInference. Converters added with RegisterType and RegisterEnum don't
+    apply to the values, and the headers must be unique
+
+The csv tag of a field starts with the column name, followed by flags and key=value options in any order,
+like `csv:"Country,required,unmarshal=ParseCountry"`. A value holding commas is quoted with single quotes, and so is
+a column name holding commas or surrounding spaces, like `csv:"'Amount, EUR',required"`.
+The positional form, `csv:"Name,Unmarshal,Marshal,required"`, is also accepted, and unknown options are
+reported as errors.
+
+A field tagged with the option default=value, like `csv:"Country,default=DK"`, gets the value when its
+column is missing or its cell is blank. The value is converted the same way as the cells of the field.
+
+Exports repeating the columns of a master on every row of its details are decoded into one struct per master,
+with the rows in a slice field tagged detail. The master columns tagged key tell which rows belong together:
+
+	type Order struct {
+		ID       int         `csv:"OrderID,key"`
+		Customer string      `csv:"Customer"`
+		Lines    []OrderLine `csv:",detail"`
+	}
+
+The master fields are decoded from the first row of a group, and rows with only blank detail columns add no
+detail. Groups are contiguous rows with the same key unless Options.Unsorted is set.
+
+Fields tagged with the option meta are filled with where the record came from instead of a column:
+
+	Line int    `csv:",meta=line"` // the line the record starts on
+	Row  string `csv:",meta=raw"`  // the fields joined by the delimiter, see Record.Raw
+	File string `csv:",meta=file"` // Options.FileName
+
+Meta fields are not written by the Encoder.
+
+The math/big types big.Int, big.Float and big.Rat, and pointers to them, are read as base 10 numbers,
+so amounts keep all their digits. Other types can be plugged in with Decoder.RegisterType.
+
+The option transform, like `csv:"Code,transform=trim|upper"`, rewrites the cells of a field before they are
+converted. See Transform for the built in transforms.
 */
 func Unmarshal(v interface{}, options *Options, data []byte) error {
 	ioreader := strings.NewReader(string(data))
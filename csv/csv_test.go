@@ -1,11 +1,14 @@
 package csv
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 //region Simple Test
@@ -488,3 +491,404 @@ func TestNewDecoder(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoder_DecodeRecord(t *testing.T) {
+	decoder, err := NewDecoder(strings.NewReader("Name,Age\nBob,12\n"), nil)
+	if err != nil {
+		t.Fatalf("NewDecoder() error = %v", err)
+	}
+
+	record, err := decoder.Reader().ReadRecord()
+	if err != nil {
+		t.Fatalf("Reader.ReadRecord() error = %v", err)
+	}
+
+	var got Simple
+	if err := decoder.DecodeRecord(record, &got); err != nil {
+		t.Fatalf("Decoder.DecodeRecord() error = %v", err)
+	}
+	if want := (Simple{"Bob", 12}); got != want {
+		t.Errorf("Decoder.DecodeRecord() = %v, want %v", got, want)
+	}
+
+	if err := decoder.DecodeRecord(record, got); err == nil {
+		t.Errorf("Decoder.DecodeRecord() with non-pointer expected an error")
+	}
+}
+
+type sliceRecordReader [][]string
+
+func (r *sliceRecordReader) Read() ([]string, error) {
+	if len(*r) == 0 {
+		return nil, io.EOF
+	}
+	record := (*r)[0]
+	*r = (*r)[1:]
+	return record, nil
+}
+
+func TestNewDecoderFromRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		reader  RecordReader
+		headers []string
+		want    *[]Simple
+		wantErr bool
+	}{
+		{
+			name:   "HeadersInRecords",
+			reader: &sliceRecordReader{{"Age", "Name"}, {"12", "Bob"}, {"13", "Alice"}},
+			want:   &[]Simple{{"Bob", 12}, {"Alice", 13}},
+		},
+		{
+			name:    "HeadersInOptions",
+			reader:  &sliceRecordReader{{"Bob", "12"}},
+			headers: SimpleHeaders,
+			want:    &[]Simple{{"Bob", 12}},
+		},
+		{
+			name:    "ReaderNil",
+			reader:  nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := NewDecoderFromRecords(tt.reader, &Options{Headers: tt.headers})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDecoderFromRecords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := &[]Simple{}
+			if err := decoder.Decode(got); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decoder.Decode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Targets(t *testing.T) {
+	data := []byte("Name,Age\nBob,12\nAlice,13\n")
+	single := []byte("Name,Age\nBob,12\n")
+
+	tests := []struct {
+		name    string
+		v       interface{}
+		data    []byte
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "SliceOfPointers",
+			v:    &[]*Simple{},
+			data: data,
+			want: &[]*Simple{{"Bob", 12}, {"Alice", 13}},
+		},
+		{
+			name: "Array",
+			v:    &[3]Simple{{"Old", 1}, {"Old", 2}, {"Old", 3}},
+			data: data,
+			want: &[3]Simple{{"Bob", 12}, {"Alice", 13}, {}},
+		},
+		{
+			name: "ArrayOfPointers",
+			v:    &[2]*Simple{},
+			data: data,
+			want: &[2]*Simple{{"Bob", 12}, {"Alice", 13}},
+		},
+		{
+			name:    "ArrayTooShort",
+			v:       &[1]Simple{},
+			data:    data,
+			want:    &[1]Simple{},
+			wantErr: "can't decode more than 1 records into array [1]csv.Simple",
+		},
+		{
+			name: "Struct",
+			v:    &Simple{},
+			data: single,
+			want: &Simple{"Bob", 12},
+		},
+		{
+			name:    "StructTooManyRecords",
+			v:       &Simple{},
+			data:    data,
+			want:    &Simple{},
+			wantErr: "can't decode more than one record into csv.Simple, use a slice",
+		},
+		{
+			name:    "StructNoRecords",
+			v:       &Simple{},
+			data:    []byte("Name,Age\n"),
+			want:    &Simple{},
+			wantErr: "can't decode into csv.Simple, there are no records",
+		},
+		{
+			name:    "SliceOfInts",
+			v:       &[]int{},
+			data:    data,
+			want:    &[]int{},
+			wantErr: "can't decode into slice element int, want a struct or a pointer to a struct",
+		},
+		{
+			name:    "Int",
+			v:       new(int),
+			data:    data,
+			want:    new(int),
+			wantErr: "can't decode into int, want a struct, or a slice or array of structs",
+		},
+		{
+			name:    "Nil",
+			v:       nil,
+			data:    data,
+			want:    nil,
+			wantErr: "csv: Unmarshal(nil)",
+		},
+		{
+			name:    "NonPointer",
+			v:       []Simple{},
+			data:    data,
+			want:    []Simple{},
+			wantErr: "csv: Unmarshal(non-pointer []csv.Simple)",
+		},
+		{
+			name:    "NilPointer",
+			v:       (*[]Simple)(nil),
+			data:    data,
+			want:    (*[]Simple)(nil),
+			wantErr: "csv: Unmarshal(nil *[]csv.Simple)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal(tt.v, nil, tt.data)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("Unmarshal() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.v, tt.want) {
+				t.Errorf("Unmarshal() = %v, want %v", tt.v, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_DecodeAppend(t *testing.T) {
+	got := &[]Simple{{"Eve", 11}}
+
+	for _, data := range []string{"Name,Age\nBob,12\n", "Name,Age\nAlice,13\n"} {
+		if err := Unmarshal(got, &Options{Append: true}, []byte(data)); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+	}
+
+	if want := &[]Simple{{"Eve", 11}, {"Bob", 12}, {"Alice", 13}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %v, want %v", got, want)
+	}
+
+	// A failing decode leaves the slice as it was
+	if err := Unmarshal(got, &Options{Append: true}, []byte("Name,Age\nBob,12\nAlice,three\n")); err == nil {
+		t.Fatalf("Unmarshal() expected an error")
+	}
+	if len(*got) != 3 {
+		t.Errorf("Unmarshal() changed the slice to %v", got)
+	}
+}
+
+func TestUnmarshal_Projection(t *testing.T) {
+	got := &[]WideRecord{}
+	if err := Unmarshal(got, nil, wideCSV(200, 3)); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := &[]WideRecord{{0, 0, 0, 0, 0}, {0, 10, 50, 100, 199}, {0, 20, 100, 200, 398}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %v, want %v", got, want)
+	}
+}
+
+// Generated decodes itself the way code from cmd/csvgen would, upper casing the name to show it was used
+type Generated struct {
+	Name string
+	Age  int
+}
+
+func (g *Generated) DecodeCSV(row *Record) error {
+	if text, found, err := row.Lookup("Name", true); err != nil {
+		return err
+	} else if found {
+		g.Name = strings.ToUpper(text)
+	}
+
+	if text, found, err := row.Lookup("Age", false); err != nil {
+		return err
+	} else if found {
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return err
+		}
+		g.Age = n
+	}
+
+	return nil
+}
+
+func (g *Generated) EncodeCSV(row *Record) error {
+	row.Set("Name", strings.ToLower(g.Name))
+	row.Set("Age", strconv.Itoa(g.Age))
+	return nil
+}
+
+func TestUnmarshal_RecordUnmarshaler(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Generated
+		wantErr bool
+	}{
+		{name: "all columns", data: "Name,Age\nBob,12\nAlice,13\n", want: []Generated{{"BOB", 12}, {"ALICE", 13}}},
+		{name: "optional missing", data: "Name\nBob\n", want: []Generated{{"BOB", 0}}},
+		{name: "required missing", data: "Age\n12\n", wantErr: true},
+		{name: "invalid", data: "Name,Age\nBob,twelve\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []Generated{}
+			err := Unmarshal(&got, nil, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	typed, err := NewTypedDecoder[Generated](strings.NewReader("Name,Age\nBob,12\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := typed.Next(); err != nil || got != (Generated{"BOB", 12}) {
+		t.Errorf("Next() = %v, %v, want %v", got, err, Generated{"BOB", 12})
+	}
+}
+
+// Registered converters are unknown to generated methods, so the structs are converted with reflection
+func TestRecordUnmarshaler_Registered(t *testing.T) {
+	words := map[string]int{"twelve": 12}
+
+	decoder, err := NewDecoder(strings.NewReader("Name,Age\nBob,twelve\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder.RegisterType(0, func(v interface{}, text []byte) error {
+		*v.(*int) = words[string(text)]
+		return nil
+	})
+
+	got := []Generated{}
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if want := []Generated{{"Bob", 12}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %v, want %v", got, want)
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder, err := NewEncoder(buffer, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoder.RegisterType(0, func(v interface{}) ([]byte, error) {
+		return []byte("twelve"), nil
+	})
+	if err := encoder.Encode(got); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if want := "Name,Age\nBob,twelve\n"; buffer.String() != want {
+		t.Errorf("Encode() = %q, want %q", buffer.String(), want)
+	}
+}
+
+type Defaulted struct {
+	Name    string
+	Country string    `csv:"Country,,,,default=DK"`
+	Age     int       `csv:"Age,,,,default=18"`
+	Since   time.Time `csv:"Since,,,,default=2020-01-01T00:00:00Z"`
+	Active  bool      `csv:"Active,DecodeActive,,,default=yes"`
+}
+
+func (*Defaulted) DecodeActive(v *bool, text []byte) error {
+	*v = string(text) == "yes"
+	return nil
+}
+
+type Kept struct {
+	Name string
+	Tags []byte `csv:"Tags,DecodeTags,,,default=none"`
+}
+
+// DecodeTags keeps the text, to check that it isn't shared between records
+func (*Kept) DecodeTags(v *[]byte, text []byte) error {
+	*v = text
+	return nil
+}
+
+func TestUnmarshal_Default(t *testing.T) {
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		data string
+		want []Defaulted
+	}{
+		{
+			name: "missing columns",
+			data: "Name\nBob\n",
+			want: []Defaulted{{"Bob", "DK", 18, since, true}},
+		},
+		{
+			name: "blank cells",
+			data: "Name,Country,Age,Since,Active\nBob,,,,\nAlice,SE,30,2021-01-01T00:00:00Z,no\n",
+			want: []Defaulted{{"Bob", "DK", 18, since, true}, {"Alice", "SE", 30, since.AddDate(1, 0, 0), false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []Defaulted{}
+			if err := Unmarshal(&got, nil, []byte(tt.data)); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The rows must not share the text of the default
+	kept := []Kept{}
+	if err := Unmarshal(&kept, nil, []byte("Name\nBob\nAlice\n")); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	kept[0].Tags[0] = 'X'
+	if string(kept[1].Tags) != "none" {
+		t.Errorf("Unmarshal() default of the second row = %q, want %q", kept[1].Tags, "none")
+	}
+
+	invalid := &[]struct {
+		Age int `csv:"Age,,,,default=old"`
+	}{}
+	if err := Unmarshal(invalid, nil, []byte("Name\nBob\n")); err == nil {
+		t.Errorf("Unmarshal() expected an error for an invalid default")
+	}
+}
@@ -0,0 +1,222 @@
+package csv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+/*
+An Encoder writes CSV values to an output stream.
+*/
+type Encoder struct {
+	writer      csvWriter
+	headers     headerList
+	headerMap   headerMap
+	writeHeader bool
+	encoders    map[reflect.Type]*recordEncoder
+	converters  map[reflect.Type]objectMarshaler
+}
+
+/*
+NewEncoder returns a new encoder that writes to w.
+
+If options.Headers is nil the headers are taken from the fields of the first struct encoded and written as the first csv record.
+If options.Columns is set the records are written as fixed-width columns and no header record is written.
+*/
+func NewEncoder(w io.Writer, options *Options) (*Encoder, error) {
+	if w == nil {
+		return nil, fmt.Errorf("writer can't be nil")
+	}
+
+	var headers headerList
+	if options != nil {
+		headers = options.Headers
+	}
+
+	var writer csvWriter
+	if options != nil && options.Columns != nil {
+		if err := validateColumns(options.Columns); err != nil {
+			return nil, err
+		}
+		if headers == nil {
+			headers = fixedWidthHeaders(options.Columns)
+		}
+		writer = newFixedWidthWriter(w, options.Columns)
+	} else {
+		writer = newWriter(w, options)
+	}
+
+	encoder := &Encoder{
+		writer:      writer,
+		headers:     headers,
+		writeHeader: headers == nil,
+		encoders:    map[reflect.Type]*recordEncoder{},
+		converters:  map[reflect.Type]objectMarshaler{},
+	}
+	if options != nil {
+		for _, converter := range options.Types {
+			if converter.Marshal != nil {
+				encoder.converters[reflect.TypeOf(converter.Value)] = converter.Marshal
+			}
+		}
+		for _, enum := range options.Enums {
+			encoder.converters[enum.typ] = enum
+		}
+	}
+
+	return encoder, nil
+}
+
+/*
+Encode writes the CSV encoding of v to the stream.
+
+v can be a struct, a slice or array of structs, or pointers to any of these.
+*/
+func (e *Encoder) Encode(v interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if err := e.encodeValue(value); err != nil {
+			return err
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := e.encodeValue(value.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("can't encode %T, want a struct or a slice of structs", v)
+	}
+
+	return e.writer.Flush()
+}
+
+func (e *Encoder) encodeValue(value reflect.Value) error {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return fmt.Errorf("can't encode nil %v", value.Type())
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("can't encode %v, want a struct", value.Type())
+	}
+
+	// Fields are read through pointers, so the value must be addressable
+	if !value.CanAddr() {
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+
+	encoder, err := e.recordEncoder(value.Type())
+	if err != nil {
+		return err
+	}
+
+	if hook, ok := value.Addr().Interface().(BeforeEncoder); ok {
+		if err := hook.BeforeEncodeCSV(); err != nil {
+			return err
+		}
+	}
+
+	if e.generated(value.Type()) {
+		marshaler := value.Addr().Interface().(RecordMarshaler)
+		record := &Record{fields: make(csvRecord, len(e.headers)), headers: e.headerMap}
+		if err := marshaler.EncodeCSV(record); err != nil {
+			return err
+		}
+		return e.writer.Write(record.fields)
+	}
+
+	record, err := encoder.Marshal(structRecord(value))
+	if err != nil {
+		return err
+	}
+
+	return e.writer.Write(record)
+}
+
+/*
+RegisterEnum makes the encoder write fields of the type of enum by name, unless their tag names a method or an enum.
+*/
+func (e *Encoder) RegisterEnum(enum *Enum) {
+	e.register(enum.typ, enum)
+}
+
+/*
+RegisterType makes the encoder write fields of the type of v with marshal, unless their tag names a method or an enum.
+marshal is called with a pointer to the field. See Decoder.RegisterType.
+*/
+func (e *Encoder) RegisterType(v interface{}, marshal MarshalFunc) {
+	e.register(reflect.TypeOf(v), marshal)
+}
+
+// generated tells if structs of type t are encoded by their EncodeCSV method, see Decoder.generated
+func (e *Encoder) generated(t reflect.Type) bool {
+	return len(e.converters) == 0 && reflect.PtrTo(t).Implements(recordMarshalerType)
+}
+
+// register sets the converter of a type, and drops the record encoders made without it
+func (e *Encoder) register(t reflect.Type, converter objectMarshaler) {
+	e.converters[t] = converter
+	e.encoders = map[reflect.Type]*recordEncoder{}
+}
+
+func (e *Encoder) recordEncoder(t reflect.Type) (*recordEncoder, error) {
+	if encoder, found := e.encoders[t]; found {
+		return encoder, nil
+	}
+
+	if e.headers == nil {
+		e.headers = getStructHeaders(structType{Type: t})
+	}
+	e.headerMap = e.headers.ToMap()
+
+	encoder := &recordEncoder{width: len(e.headers)}
+	if !e.generated(t) {
+		var err error
+		if encoder, err = newRecordEncoder(structType{Type: t}, e.headers, e.converters); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.writeHeader {
+		header := make(csvRecord, len(e.headers))
+		for i, name := range e.headers {
+			header[i] = []byte(name)
+		}
+		if err := e.writer.Write(header); err != nil {
+			return nil, err
+		}
+		e.writeHeader = false
+	}
+
+	e.encoders[t] = encoder
+	return encoder, nil
+}
+
+/*
+Marshal returns the CSV encoding of v.
+
+See the documentation for Encode for the values that can be encoded.
+*/
+func Marshal(v interface{}, options *Options) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+
+	encoder, err := NewEncoder(buffer, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
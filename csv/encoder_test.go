@@ -0,0 +1,173 @@
+package csv
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MarshalCustom struct {
+	Name string `csv:"Name,,MarshalName"`
+	Age  int    `csv:"Years"`
+}
+
+func (c *MarshalCustom) MarshalName(name *string) ([]byte, error) {
+	return []byte(strings.ToUpper(*name)), nil
+}
+
+type MarshalInvalidMethod struct {
+	Name string `csv:"Name,,MarshalName"`
+}
+
+func (c *MarshalInvalidMethod) MarshalName(name string) string {
+	return name
+}
+
+type MarshalError struct {
+	Name string `csv:"Name,,MarshalName"`
+}
+
+func (c *MarshalError) MarshalName(name *string) ([]byte, error) {
+	return nil, fmt.Errorf("MarshalError Name invalid")
+}
+
+type MarshalText struct {
+	Name string
+	When time.Time
+	Tags []string
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		headers []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Simple",
+			v:    []Simple{{"Bob", 12}, {"Alice", 13}},
+			want: "Name,Age\nBob,12\nAlice,13\n",
+		},
+		{
+			name: "PointerToSliceOfPointers",
+			v:    &[]*Simple{{"Bob", 12}},
+			want: "Name,Age\nBob,12\n",
+		},
+		{
+			name: "SingleStruct",
+			v:    Simple{"Bob, Jr.", 12},
+			want: "Name,Age\n\"Bob, Jr.\",12\n",
+		},
+		{
+			name:    "Headers",
+			v:       []Simple{{"Bob", 12}},
+			headers: []string{"Age", "Other", "Name"},
+			want:    "12,,Bob\n",
+		},
+		{
+			name: "CustomMarshal",
+			v:    []MarshalCustom{{"Bob", 12}},
+			want: "Name,Years\nBOB,12\n",
+		},
+		{
+			name: "TextAndJSON",
+			v:    []MarshalText{{"Bob", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), []string{"a"}}},
+			want: "Name,When,Tags\nBob,2020-01-02T03:04:05Z,\"[\"\"a\"\"]\"\n",
+		},
+		{
+			name:    "ErrorMissingRequired",
+			v:       []ErrorMissingRequired{{"Bob", 12}},
+			headers: ErrorMissingRequiredHeaders,
+			wantErr: true,
+		},
+		{
+			name:    "ErrorInvalidMethod",
+			v:       []MarshalInvalidMethod{{"Bob"}},
+			wantErr: true,
+		},
+		{
+			name:    "ErrorMarshal",
+			v:       []MarshalError{{"Bob"}},
+			wantErr: true,
+		},
+		{
+			name:    "ErrorNotStruct",
+			v:       []int{1, 2},
+			wantErr: true,
+		},
+		{
+			name:    "ErrorNilPointer",
+			v:       []*Simple{nil},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.v, &Options{Headers: tt.headers})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Marshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("Marshal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	want := &[]MarshalText{{"Bob", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), []string{"a", "b"}}}
+
+	data, err := Marshal(want, nil)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	got := &[]MarshalText{}
+	if err := Unmarshal(got, nil, data); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal(Marshal()) = %v, want %v", got, want)
+	}
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	encoder, err := NewEncoder(buffer, &Options{Comma: ';'})
+	if err != nil {
+		t.Fatalf("NewEncoder() error = %v", err)
+	}
+
+	// The header is only written once
+	for _, record := range []Simple{{"Bob", 12}, {"Alice", 13}} {
+		if err := encoder.Encode(record); err != nil {
+			t.Fatalf("Encoder.Encode() error = %v", err)
+		}
+	}
+
+	if got, want := buffer.String(), "Name;Age\nBob;12\nAlice;13\n"; got != want {
+		t.Errorf("Encoder.Encode() = %q, want %q", got, want)
+	}
+
+	if _, err := NewEncoder(nil, nil); err == nil {
+		t.Errorf("NewEncoder() with nil writer expected an error")
+	}
+}
+
+func TestMarshal_RecordMarshaler(t *testing.T) {
+	got, err := Marshal([]Generated{{"BOB", 12}, {"ALICE", 13}}, nil)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if want := "Name,Age\nbob,12\nalice,13\n"; string(got) != want {
+		t.Errorf("Marshal() = %q, want %q", got, want)
+	}
+}
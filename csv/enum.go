@@ -0,0 +1,136 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+/*
+Enum maps the names in a csv file to the values of a type, like the constants of a type Status int.
+
+An Enum is registered for its type with Decoder.RegisterEnum and Encoder.RegisterEnum, or declared on a field with
+the tag options enum and ignorecase:
+
+	Status Status `csv:"Status,enum=ACTIVE:1|SUSPENDED:2,ignorecase"`
+
+The values in the tag are read the same way as a cell of the type. The Encoder writes the name of a value, the first
+in sorted order if a value has more than one name. Names the Enum doesn't know are reported as an *EnumError.
+*/
+type Enum struct {
+	typ        reflect.Type
+	values     map[string]reflect.Value
+	names      map[interface{}]string
+	ignoreCase bool
+}
+
+/*
+NewEnum returns an Enum of the names and values. If ignoreCase is true the names are matched without regard to case.
+*/
+func NewEnum[T comparable](values map[string]T, ignoreCase bool) *Enum {
+	enum := newEnum(reflect.TypeOf((*T)(nil)).Elem(), ignoreCase)
+	for name, value := range values {
+		enum.add(name, reflect.ValueOf(value))
+	}
+	return enum
+}
+
+func newEnum(t reflect.Type, ignoreCase bool) *Enum {
+	return &Enum{
+		typ:        t,
+		values:     map[string]reflect.Value{},
+		names:      map[interface{}]string{},
+		ignoreCase: ignoreCase,
+	}
+}
+
+func (e *Enum) add(name string, value reflect.Value) {
+	e.values[e.key(name)] = value
+
+	// The first name in sorted order is written for a value
+	if current, found := e.names[value.Interface()]; !found || name < current {
+		e.names[value.Interface()] = name
+	}
+}
+
+func (e *Enum) key(name string) string {
+	if e.ignoreCase {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// Names returns the names of the Enum in sorted order
+func (e *Enum) Names() []string {
+	names := make([]string, 0, len(e.names))
+	for _, name := range e.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unmarshal stores the value of the name in text in v, which must be a pointer to the type of the Enum
+func (e *Enum) Unmarshal(v interface{}, text []byte) error {
+	value, found := e.values[e.key(string(text))]
+	if !found {
+		return &EnumError{Type: e.typ, Value: string(text), Names: e.Names()}
+	}
+
+	reflect.ValueOf(v).Elem().Set(value)
+	return nil
+}
+
+// Marshal returns the name of the value pointed to by v
+func (e *Enum) Marshal(v interface{}) ([]byte, error) {
+	value := reflect.ValueOf(v).Elem().Interface()
+
+	name, found := e.names[value]
+	if !found {
+		return nil, &EnumError{Type: e.typ, Value: fmt.Sprint(value), Names: e.Names()}
+	}
+
+	return []byte(name), nil
+}
+
+/*
+An EnumError describes a name, or a value when encoding, that an Enum doesn't know.
+The Decoder returns it wrapped in a *RecordError with the line and the column.
+*/
+type EnumError struct {
+	Type  reflect.Type
+	Value string
+	Names []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("invalid %v %q, want one of %v", e.Type, e.Value, strings.Join(e.Names, ", "))
+}
+
+// getTagEnum returns the Enum declared by the enum option of the field, like enum=ACTIVE:1|SUSPENDED:2
+func getTagEnum(field fieldInfo) (*Enum, error) {
+	if !field.Type.Comparable() {
+		return nil, fmt.Errorf("invalid enum on field %v, %v is not comparable", field.Name, field.Type)
+	}
+
+	_, ignoreCase := field.Options["ignorecase"]
+	enum := newEnum(field.Type, ignoreCase)
+	unmarshaler := nativeUnmarshal(field.Type)
+
+	for _, pair := range strings.Split(field.Options["enum"], "|") {
+		i := strings.Index(pair, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid enum value %q on field %v, want name:value", pair, field.Name)
+		}
+
+		value := reflect.New(field.Type)
+		if err := unmarshaler.Unmarshal(value.Interface(), []byte(pair[i+1:])); err != nil {
+			return nil, fmt.Errorf("invalid enum value %q on field %v: %v", pair, field.Name, err)
+		}
+
+		enum.add(pair[:i], value.Elem())
+	}
+
+	return enum, nil
+}
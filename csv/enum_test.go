@@ -0,0 +1,216 @@
+package csv
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Status int
+
+const (
+	Active Status = iota + 1
+	Suspended
+)
+
+var statusEnum = NewEnum(map[string]Status{"ACTIVE": Active, "SUSPENDED": Suspended}, false)
+
+type Account struct {
+	Name   string
+	Status Status
+}
+
+type TaggedAccount struct {
+	Name   string
+	Status Status `csv:"Status,enum=ACTIVE:1|SUSPENDED:2|ON:1,ignorecase,default=active"`
+}
+
+func TestDecoder_RegisterEnum(t *testing.T) {
+	d, err := NewDecoder(strings.NewReader("Name,Status\nBob,ACTIVE\nAlice,SUSPENDED\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.RegisterEnum(statusEnum)
+
+	got := []Account{}
+	if err := d.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if want := []Account{{"Bob", Active}, {"Alice", Suspended}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %v, want %v", got, want)
+	}
+
+	// Without the enum the names are not numbers
+	if err := Unmarshal(&got, nil, []byte("Name,Status\nBob,ACTIVE\n")); err == nil {
+		t.Errorf("Unmarshal() expected an error without the enum")
+	}
+}
+
+func TestEncoder_RegisterEnum(t *testing.T) {
+	var buf bytes.Buffer
+	e, err := NewEncoder(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.RegisterEnum(statusEnum)
+
+	if err := e.Encode([]Account{{"Bob", Active}, {"Alice", Suspended}}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	if want := "Name,Status\nBob,ACTIVE\nAlice,SUSPENDED\n"; buf.String() != want {
+		t.Errorf("Encode() = %q, want %q", buf.String(), want)
+	}
+
+	var enumErr *EnumError
+	if err := e.Encode(Account{"Eve", 7}); !errors.As(err, &enumErr) {
+		t.Errorf("Encode() error = %v, want an EnumError", err)
+	}
+}
+
+func TestUnmarshal_TagEnum(t *testing.T) {
+	got := []TaggedAccount{}
+	if err := Unmarshal(&got, nil, []byte("Name,Status\nBob,Active\nAlice,suspended\nEve,on\nMallory,\n")); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := []TaggedAccount{{"Bob", Active}, {"Alice", Suspended}, {"Eve", Active}, {"Mallory", Active}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %v, want %v", got, want)
+	}
+
+	// The first name in sorted order is written
+	data, err := Marshal(want[:2], nil)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := "Name,Status\nBob,ACTIVE\nAlice,SUSPENDED\n"; string(data) != want {
+		t.Errorf("Marshal() = %q, want %q", data, want)
+	}
+}
+
+func TestUnmarshal_EnumErrors(t *testing.T) {
+	var recordErr *RecordError
+	var enumErr *EnumError
+	err := Unmarshal(&[]TaggedAccount{}, nil, []byte("Name,Status\nBob,ACTIVE\nAlice,CLOSED\n"))
+	if !errors.As(err, &recordErr) || !errors.As(err, &enumErr) {
+		t.Fatalf("Unmarshal() error = %v, want an EnumError in a RecordError", err)
+	}
+	if recordErr.Line != 3 || recordErr.Column != "Status" || enumErr.Value != "CLOSED" {
+		t.Errorf("Unmarshal() error = %v, want line 3, column Status and value CLOSED", err)
+	}
+
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{name: "missing value", v: &[]struct {
+			Status Status `csv:"Status,enum=ACTIVE"`
+		}{}},
+		{name: "invalid value", v: &[]struct {
+			Status Status `csv:"Status,enum=ACTIVE:one"`
+		}{}},
+		{name: "not comparable", v: &[]struct {
+			Status []int `csv:"Status,enum=ACTIVE:[1]"`
+		}{}},
+		{name: "ignorecase without enum", v: &[]struct {
+			Status string `csv:"Status,ignorecase"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unmarshal(tt.v, nil, []byte("Status\nACTIVE\n")); err == nil {
+				t.Errorf("Unmarshal() expected an error")
+			}
+		})
+	}
+}
+
+type DefaultedAccount struct {
+	Name   string
+	Status Status `csv:"Status,default=ACTIVE"`
+}
+
+// Defaults are converted when the struct is checked, so the enum must be known before
+func TestOptions_Enums(t *testing.T) {
+	data := "Name,Status\nBob,\nAlice,SUSPENDED\n"
+	want := []DefaultedAccount{{"Bob", Active}, {"Alice", Suspended}}
+
+	if _, err := NewTypedDecoder[DefaultedAccount](strings.NewReader(data), nil); err == nil {
+		t.Errorf("NewTypedDecoder() expected an error without the enum")
+	}
+
+	typed, err := NewTypedDecoder[DefaultedAccount](strings.NewReader(data), &Options{Enums: []*Enum{statusEnum}})
+	if err != nil {
+		t.Fatalf("NewTypedDecoder() error = %v", err)
+	}
+	got := []DefaultedAccount{}
+	for account, err := range typed.All() {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		got = append(got, account)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+
+	m, err := NewMultiDecoder(strings.NewReader("Kind,Name,Status\nA,Bob,\n"), "Kind", &Options{Enums: []*Enum{statusEnum}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register("A", DefaultedAccount{}, []string{"Kind", "Name", "Status"}); err != nil {
+		t.Errorf("Register() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	e, err := NewEncoder(&buf, &Options{Enums: []*Enum{statusEnum}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Encode(want); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if want := "Name,Status\nBob,ACTIVE\nAlice,SUSPENDED\n"; buf.String() != want {
+		t.Errorf("Encode() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestOptions_Types(t *testing.T) {
+	type row struct {
+		Name  string
+		Score int `csv:"Score,default=none"`
+	}
+
+	words := map[string]int{"none": 0, "one": 1}
+	options := &Options{Types: []TypeConverter{{
+		Value: 0,
+		Unmarshal: func(v interface{}, text []byte) error {
+			n, found := words[string(text)]
+			if !found {
+				return errors.New("unknown word")
+			}
+			*v.(*int) = n
+			return nil
+		},
+	}}}
+
+	typed, err := NewTypedDecoder[row](strings.NewReader("Name,Score\nBob,\nAlice,one\n"), options)
+	if err != nil {
+		t.Fatalf("NewTypedDecoder() error = %v", err)
+	}
+	got := []row{}
+	for r, err := range typed.All() {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		got = append(got, r)
+	}
+	if want := []row{{"Bob", 0}, {"Alice", 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+/*
+An InvalidUnmarshalError describes an invalid argument passed to Unmarshal or Decode.
+The argument must be a non-nil pointer.
+*/
+type InvalidUnmarshalError struct {
+	Type reflect.Type
+}
+
+func (e *InvalidUnmarshalError) Error() string {
+	if e.Type == nil {
+		return "csv: Unmarshal(nil)"
+	}
+
+	if e.Type.Kind() != reflect.Ptr {
+		return "csv: Unmarshal(non-pointer " + e.Type.String() + ")"
+	}
+
+	return "csv: Unmarshal(nil " + e.Type.String() + ")"
+}
+
+/*
+A RecordError reports an error for the record on the given line, and the column if it is about a single field.
+*/
+type RecordError struct {
+	Line   int
+	Column string
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("csv: record on line %d, column %v: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("csv: record on line %d: %v", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
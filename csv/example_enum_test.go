@@ -0,0 +1,38 @@
+package csv_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+type Level int
+
+const (
+	Low Level = iota
+	High
+)
+
+type Alert struct {
+	Name  string
+	Level Level
+}
+
+func ExampleDecoder_RegisterEnum() {
+	decoder, err := csv.NewDecoder(strings.NewReader("Name,Level\ndisk,HIGH\ncpu,low\n"), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	decoder.RegisterEnum(csv.NewEnum(map[string]Level{"LOW": Low, "HIGH": High}, true))
+
+	var alerts []Alert
+	if err := decoder.Decode(&alerts); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(alerts)
+	// Output:
+	// [{disk 1} {cpu 0}]
+}
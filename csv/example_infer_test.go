@@ -0,0 +1,25 @@
+package csv_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+func ExampleInferRule() {
+	data := []byte("SKU,Price,InStock\n007,9.95,true\n")
+
+	var rows []map[string]interface{}
+	if err := csv.Unmarshal(&rows, &csv.Options{Infer: csv.DefaultInference}, data); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, header := range []string{"SKU", "Price", "InStock"} {
+		fmt.Printf("%v: %T %v\n", header, rows[0][header], rows[0][header])
+	}
+	// Output:
+	// SKU: string 007
+	// Price: float64 9.95
+	// InStock: bool true
+}
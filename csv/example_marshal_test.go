@@ -0,0 +1,57 @@
+package csv_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+type MarshalRecord struct {
+	Name string
+	Age  int `csv:"Years"`
+}
+
+func ExampleMarshal() {
+	records := []MarshalRecord{
+		{"Bob", 12},
+		{"Sally", 13},
+	}
+
+	data, err := csv.Marshal(records, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Print(string(data))
+	// Output:
+	// Name,Years
+	// Bob,12
+	// Sally,13
+}
+
+type FixedWidthRecord struct {
+	ID   int    `csv:"ID,,,,fixed=0:4,pad=0,align=right"`
+	Name string `csv:"Name,,,,fixed=4:6"`
+}
+
+var fixedwidth = []byte(`0012Bob   
+0345Alice 
+`)
+
+func ExampleFixedWidthColumns() {
+	var records []FixedWidthRecord
+
+	columns, err := csv.FixedWidthColumns(&records)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := csv.Unmarshal(&records, &csv.Options{Columns: columns}, fixedwidth); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(records)
+	// Output:
+	// [{12 Bob} {345 Alice}]
+}
@@ -0,0 +1,73 @@
+package csv_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+// Answer is written as Y or N in csv files, and as yes or no everywhere else
+type Answer bool
+
+func (a *Answer) UnmarshalCSV(text []byte) error {
+	switch string(text) {
+	case "Y":
+		*a = true
+	case "N":
+		*a = false
+	default:
+		return fmt.Errorf("invalid answer %q", text)
+	}
+	return nil
+}
+
+func (a *Answer) MarshalCSV() ([]byte, error) {
+	if *a {
+		return []byte("Y"), nil
+	}
+	return []byte("N"), nil
+}
+
+func (a *Answer) UnmarshalText(text []byte) error {
+	*a = string(text) == "yes"
+	return nil
+}
+
+func (a Answer) MarshalText() ([]byte, error) {
+	if a {
+		return []byte("yes"), nil
+	}
+	return []byte("no"), nil
+}
+
+type Survey struct {
+	Name   string
+	Answer Answer
+}
+
+func ExampleUnmarshaler() {
+	var surveys []Survey
+
+	if err := csv.Unmarshal(&surveys, nil, []byte("Name,Answer\nBob,Y\nAlice,N\n")); err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := json.Marshal(surveys)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(data))
+
+	data, err = csv.Marshal(surveys, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(string(data))
+	// Output:
+	// [{"Name":"Bob","Answer":"yes"},{"Name":"Alice","Answer":"no"}]
+	// Name,Answer
+	// Bob,Y
+	// Alice,N
+}
@@ -0,0 +1,59 @@
+package csv_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+// Cents stands in for the decimal type of a decimal library
+type Cents int64
+
+type Payment struct {
+	Payee  string
+	Amount Cents
+}
+
+func parseCents(v interface{}, text []byte) error {
+	units, fraction, _ := strings.Cut(string(text), ".")
+	fraction = (fraction + "00")[:2]
+	cents, err := strconv.ParseInt(units+fraction, 10, 64)
+	*v.(*Cents) = Cents(cents)
+	return err
+}
+
+func formatCents(v interface{}) ([]byte, error) {
+	cents := int64(*v.(*Cents))
+	return []byte(fmt.Sprintf("%d.%02d", cents/100, cents%100)), nil
+}
+
+func ExampleDecoder_RegisterType() {
+	decoder, err := csv.NewDecoder(strings.NewReader("Payee,Amount\nAlice,12.5\nBob,0.07\n"), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	decoder.RegisterType(Cents(0), parseCents)
+
+	var payments []Payment
+	if err := decoder.Decode(&payments); err != nil {
+		log.Fatal(err)
+	}
+
+	encoder, err := csv.NewEncoder(os.Stdout, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	encoder.RegisterType(Cents(0), formatCents)
+
+	if err := encoder.Encode(payments); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// Payee,Amount
+	// Alice,12.50
+	// Bob,0.07
+}
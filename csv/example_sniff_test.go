@@ -0,0 +1,42 @@
+package csv_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+var semicoloncsv = `Name;Age
+Bob;12
+Sally;13
+`
+
+func ExampleSniff() {
+	options, err := csv.Sniff(strings.NewReader(semicoloncsv))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%q %v\n", options.Comma, options.NoHeader)
+	// Output:
+	// ';' false
+}
+
+func ExampleOptions_sniff() {
+	var records []SimpleRecord
+
+	decoder, err := csv.NewDecoder(strings.NewReader(semicoloncsv), &csv.Options{Sniff: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := decoder.Decode(&records); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(records)
+	// Output:
+	// [{Bob 12} {Sally 13}]
+}
@@ -0,0 +1,27 @@
+package csv_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+func ExampleNewTypedDecoder() {
+	decoder, err := csv.NewTypedDecoder[SimpleRecord](bytes.NewReader(simplecsv), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for record, err := range decoder.All() {
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(record.Name, record.Age)
+	}
+	// Output:
+	// Bob 12
+	// Sally 13
+	// Alice 10
+}
@@ -1,24 +1,51 @@
 package csv
 
+import "reflect"
+
 type fieldDecoder struct {
+	name         string
 	recordIndex  int
 	structIndex  []int
 	unmarshaller objectUnmarshaler
+	validators   []validator
+	transforms   []transformStep
+
+	// defaultValue is decoded instead of blank cells, and missing columns when recordIndex is -1
+	defaultValue []byte
+	hasDefault   bool
 }
 
 func (d *fieldDecoder) decode(object structRecord, record csvRecord) error {
 	// Field on object
 	objField := object.GetField(d.structIndex)
 
-	// Field in csv
-	csvField := record[d.recordIndex]
+	// Field in csv, or the default
+	var csvField []byte
+	if d.recordIndex >= 0 {
+		csvField = applyTransforms(d.transforms, record[d.recordIndex])
+	}
+	if len(csvField) == 0 && d.hasDefault {
+		// A copy for each record, as methods may keep the text, like in a []byte field
+		csvField = append([]byte(nil), d.defaultValue...)
+	}
 
 	// Unmarshal func
 	unmarshalMethod := d.unmarshaller.Unmarshal
 
 	if err := unmarshalMethod(objField, csvField); err != nil {
+		// Unknown enum names are about the cell, like validation errors
+		if _, ok := err.(*EnumError); ok {
+			return &RecordError{Column: d.name, Err: err}
+		}
 		return err
 	}
 
+	// Validate the converted field, the line is added by the Decoder
+	for _, validator := range d.validators {
+		if err := validator.validate(reflect.ValueOf(objField).Elem(), csvField); err != nil {
+			return &RecordError{Column: d.name, Err: err}
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,25 @@
+package csv
+
+type fieldEncoder struct {
+	recordIndex int
+	structIndex []int
+	marshaller  objectMarshaler
+}
+
+func (e *fieldEncoder) encode(object structRecord, record csvRecord) error {
+	// Field on object
+	objField := object.GetField(e.structIndex)
+
+	// Marshal func
+	marshalMethod := e.marshaller.Marshal
+
+	csvField, err := marshalMethod(objField)
+	if err != nil {
+		return err
+	}
+
+	// Field in csv
+	record[e.recordIndex] = csvField
+
+	return nil
+}
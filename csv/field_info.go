@@ -1,24 +1,91 @@
 package csv
 
 import (
+	"fmt"
 	"reflect"
-	"strings"
+
+	"github.com/KalleDK/go-csv/internal/tag"
 )
 
 const tagKey = "csv"
 
-func getFieldInfo(field reflect.StructField) fieldInfo {
-
-	tagList := strings.Split(field.Tag.Get(tagKey), ",")
+// tagOptions are the options known in the tag, and if they take a value
+var tagOptions = map[string]bool{
+	"required":   false,
+	"optional":   false,
+	"default":    true,
+	"notempty":   false,
+	"min":        true,
+	"max":        true,
+	"len":        true,
+	"regex":      true,
+	"oneof":      true,
+	"enum":       true,
+	"ignorecase": false,
+	"fixed":      true,
+	"pad":        true,
+	"align":      true,
+	"keeppad":    false,
+	"key":        false,
+	"detail":     false,
+	"meta":       true,
+	"transform":  true,
+}
 
-	return fieldInfo{
+/*
+getFieldInfo reads the tag of the field. The info is filled in as far as the tag could be read,
+so the name is known even when an option is invalid.
+*/
+func getFieldInfo(field reflect.StructField) (fieldInfo, error) {
+	info := fieldInfo{
 		index:      field.Index,
-		Name:       getOrDefault(tagList, 0, field.Name),
-		Unmarshal:  getOrDefault(tagList, 1, ""),
-		Marshal:    getOrDefault(tagList, 2, ""),
-		IsOptional: getOrDefault(tagList, 3, "optional") != "required",
+		Name:       field.Name,
+		IsOptional: true,
 		Type:       field.Type,
 	}
+
+	parsed, err := tag.Parse(field.Tag.Get(tagKey))
+	if parsed.Name != "" {
+		info.Name = parsed.Name
+	}
+	if err != nil {
+		return info, fmt.Errorf("invalid tag on field %v: %v", field.Name, err)
+	}
+
+	info.Unmarshal = parsed.Unmarshal
+	info.Marshal = parsed.Marshal
+
+	for _, option := range parsed.Options {
+		takesValue, known := tagOptions[option.Key]
+		switch {
+		case !known:
+			return info, fmt.Errorf("unknown option %v on field %v", option.Key, field.Name)
+		case takesValue && !option.HasValue:
+			return info, fmt.Errorf("option %v on field %v needs a value", option.Key, field.Name)
+		case !takesValue && option.HasValue:
+			return info, fmt.Errorf("option %v on field %v takes no value", option.Key, field.Name)
+		}
+
+		switch option.Key {
+		case "required":
+			info.IsOptional = false
+		case "optional":
+			info.IsOptional = true
+		default:
+			if info.Options == nil {
+				info.Options = map[string]string{}
+			}
+			info.Options[option.Key] = option.Value
+		}
+	}
+
+	if _, found := info.Options["ignorecase"]; found {
+		if _, found := info.Options["enum"]; !found {
+			return info, fmt.Errorf("option ignorecase on field %v needs an enum", field.Name)
+		}
+	}
+
+	return info, nil
 }
 
 type fieldInfo struct {
@@ -28,12 +95,5 @@ type fieldInfo struct {
 	Marshal    string
 	IsOptional bool
 	Type       reflect.Type
-}
-
-func getOrDefault(tags []string, i int, def string) string {
-	if len(tags) > i && tags[i] != "" {
-		return strings.Trim(tags[i], " ")
-	}
-
-	return def
+	Options    map[string]string
 }
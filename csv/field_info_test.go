@@ -14,9 +14,10 @@ func Test_getFieldInfo(t *testing.T) {
 		field reflect.StructField
 	}
 	tests := []struct {
-		name string
-		args args
-		want fieldInfo
+		name    string
+		args    args
+		want    fieldInfo
+		wantErr bool
 	}{
 		{
 			name: "Empty",
@@ -75,10 +76,137 @@ func Test_getFieldInfo(t *testing.T) {
 				Type:       stringType,
 			},
 		},
+		{
+			name: "Options",
+			args: args{
+				field: reflect.StructField{
+					Type:  intType,
+					Name:  "Default",
+					Index: []int{2},
+					Tag:   `csv:"MyName,min=1,required,unmarshal=MyUnmarshal,notempty"`,
+				},
+			},
+			want: fieldInfo{
+				index:      []int{2},
+				Name:       "MyName",
+				Unmarshal:  "MyUnmarshal",
+				IsOptional: false,
+				Type:       intType,
+				Options:    map[string]string{"min": "1", "notempty": ""},
+			},
+		},
+		{
+			name: "Positional with options",
+			args: args{
+				field: reflect.StructField{
+					Type:  stringType,
+					Name:  "Default",
+					Index: []int{3},
+					Tag:   `csv:",,MyMarshal,,regex='^a,b$',pad= "`,
+				},
+			},
+			want: fieldInfo{
+				index:      []int{3},
+				Name:       "Default",
+				Marshal:    "MyMarshal",
+				IsOptional: true,
+				Type:       stringType,
+				Options:    map[string]string{"regex": "^a,b$", "pad": " "},
+			},
+		},
+		{
+			name: "Unknown option",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Tag: `csv:"MyName,maximum=3"`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Misspelled required",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Tag: `csv:"MyName,,,requierd"`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Flag with value",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Tag: `csv:"MyName,required=yes"`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Option without value",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Tag: `csv:"MyName,min"`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Method given twice",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Tag: `csv:"MyName,MyUnmarshal,,unmarshal=Other"`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Quoted name",
+			args: args{
+				field: reflect.StructField{
+					Type:  intType,
+					Name:  "Default",
+					Index: []int{4},
+					Tag:   `csv:"' Amount, EUR''s ',required"`,
+				},
+			},
+			want: fieldInfo{
+				index:      []int{4},
+				Name:       " Amount, EUR's ",
+				IsOptional: false,
+				Type:       intType,
+			},
+		},
+		{
+			name: "Quoted name alone",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Index: []int{5}, Tag: `csv:"'a,b'"`},
+			},
+			want: fieldInfo{
+				index:      []int{5},
+				Name:       "a,b",
+				IsOptional: true,
+				Type:       intType,
+			},
+		},
+		{
+			name: "Unterminated quoted name",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Tag: `csv:"'a,b,required"`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Text after quoted name",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Tag: `csv:"'a'b,required"`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unterminated quote",
+			args: args{
+				field: reflect.StructField{Type: intType, Name: "Default", Tag: `csv:"MyName,oneof='a,b"`},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getFieldInfo(tt.args.field); !reflect.DeepEqual(got, tt.want) {
+			got, err := getFieldInfo(tt.args.field)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getFieldInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("getFieldInfo() = %v, want %v", got, tt.want)
 			}
 		})
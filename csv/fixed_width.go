@@ -0,0 +1,310 @@
+package csv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+Alignment tells on which side of a fixed-width column the value is placed.
+*/
+type Alignment int
+
+const (
+	// AlignLeft places the value at the start of the column and pads to the right
+	AlignLeft Alignment = iota
+	// AlignRight places the value at the end of the column and pads to the left
+	AlignRight
+)
+
+/*
+A FixedWidthColumn describes a column in a fixed-width file.
+
+In struct tags a column is declared with the options fixed=start:width, pad=c, align=left|right and keeppad
+
+	type Account struct {
+		ID      int    `csv:"ID,fixed=0:6,pad=0,align=right"`
+		Name    string `csv:"Name,fixed=6:20"`
+	}
+*/
+type FixedWidthColumn struct {
+	// Name is the header used to map the column to a struct field
+	Name string
+
+	// Start is the zero-based offset of the column in the line, counted in characters
+	Start int
+
+	// Width is the number of characters in the column
+	Width int
+
+	// Pad is the character filling the unused part of the column.
+	// It is set to space (' ') if zero.
+	Pad rune
+
+	// Align decides on which side of the column the value is placed, and so which side is padded
+	Align Alignment
+
+	// If KeepPad is true the padding is not trimmed from the value when reading
+	KeepPad bool
+}
+
+func (c FixedWidthColumn) pad() rune {
+	if c.Pad == 0 {
+		return ' '
+	}
+	return c.Pad
+}
+
+func (c FixedWidthColumn) trim(value string) string {
+	if c.KeepPad {
+		return value
+	}
+
+	pad := string(c.pad())
+	trimmed := strings.TrimRight(value, pad)
+	if c.Align == AlignRight {
+		trimmed = strings.TrimLeft(value, pad)
+	}
+
+	// A right aligned column of only pad, like 0000 for a zero padded with 0, keeps one pad character.
+	// Spaces are blank cells, and so is the pad of left aligned columns, which fill writes for empty values.
+	if trimmed == "" && value != "" && c.Align == AlignRight && c.pad() != ' ' {
+		return pad
+	}
+	return trimmed
+}
+
+func (c FixedWidthColumn) fill(value string) (string, error) {
+	count := utf8.RuneCountInString(value)
+	if count > c.Width {
+		return "", fmt.Errorf("value %q is wider than column %v (width %v)", value, c.Name, c.Width)
+	}
+
+	padding := strings.Repeat(string(c.pad()), c.Width-count)
+	if c.Align == AlignRight {
+		return padding + value, nil
+	}
+	return value + padding, nil
+}
+
+func validateColumns(columns []FixedWidthColumn) error {
+	for _, column := range columns {
+		if column.Start < 0 || column.Width <= 0 {
+			return fmt.Errorf("invalid fixed-width column %v start %v width %v", column.Name, column.Start, column.Width)
+		}
+	}
+	return nil
+}
+
+func fixedWidthHeaders(columns []FixedWidthColumn) headerList {
+	headers := make(headerList, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Name
+	}
+	return headers
+}
+
+/*
+FixedWidthColumns returns the fixed-width layout declared in the tags of the struct type of v, ordered by the start of the columns.
+
+v can be a struct, or a pointer, slice or array of structs. Fields without a fixed option are not part of the layout.
+*/
+func FixedWidthColumns(v interface{}) ([]FixedWidthColumn, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't read columns from %T, want a struct", v)
+	}
+
+	columns := []FixedWidthColumn{}
+	for i := 0; i < t.NumField(); i++ {
+		field, err := getFieldInfo(t.Field(i))
+		if err != nil {
+			return nil, err
+		}
+
+		column, found, err := getFixedWidthColumn(field)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			columns = append(columns, column)
+		}
+	}
+
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].Start < columns[j].Start
+	})
+
+	return columns, validateColumns(columns)
+}
+
+func getFixedWidthColumn(field fieldInfo) (FixedWidthColumn, bool, error) {
+	position, found := field.Options["fixed"]
+	if !found {
+		return FixedWidthColumn{}, false, nil
+	}
+
+	column := FixedWidthColumn{Name: field.Name}
+
+	parts := strings.Split(position, ":")
+	if len(parts) != 2 {
+		return column, false, fmt.Errorf("invalid fixed option %q on %v, want start:width", position, field.Name)
+	}
+
+	var err error
+	if column.Start, err = strconv.Atoi(parts[0]); err != nil {
+		return column, false, fmt.Errorf("invalid fixed option %q on %v: %v", position, field.Name, err)
+	}
+	if column.Width, err = strconv.Atoi(parts[1]); err != nil {
+		return column, false, fmt.Errorf("invalid fixed option %q on %v: %v", position, field.Name, err)
+	}
+
+	if pad, found := field.Options["pad"]; found {
+		if utf8.RuneCountInString(pad) != 1 {
+			return column, false, fmt.Errorf("invalid pad option %q on %v, want a single character", pad, field.Name)
+		}
+		column.Pad, _ = utf8.DecodeRuneInString(pad)
+	}
+
+	switch align := field.Options["align"]; align {
+	case "", "left":
+		column.Align = AlignLeft
+	case "right":
+		column.Align = AlignRight
+	default:
+		return column, false, fmt.Errorf("invalid align option %q on %v, want left or right", align, field.Name)
+	}
+
+	_, column.KeepPad = field.Options["keeppad"]
+
+	return column, true, nil
+}
+
+type fixedWidthReader struct {
+	reader  *bufio.Reader
+	columns []FixedWidthColumn
+	comment rune
+	line    int
+	offset  int64
+	runes   []rune
+}
+
+func newFixedWidthReader(r io.Reader, options *Options) *fixedWidthReader {
+	return &fixedWidthReader{
+		reader:  bufio.NewReader(r),
+		columns: options.Columns,
+		comment: options.Comment,
+	}
+}
+
+func (r *fixedWidthReader) Read() (csvRecord, error) {
+	return r.readProjected(nil)
+}
+
+func (r *fixedWidthReader) readProjected(projection columnProjection) (csvRecord, error) {
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return nil, io.EOF
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		r.line++
+		r.offset += int64(len(line))
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		// Empty lines and comments are skipped, the same way encoding/csv does
+		if line == "" || (r.comment != 0 && strings.HasPrefix(line, string(r.comment))) {
+			continue
+		}
+
+		r.runes = []rune(line)
+
+		record := make(csvRecord, len(r.columns))
+		for i, column := range r.columns {
+			if projection.needs(i) {
+				record[i] = []byte(column.trim(string(r.slice(column.Start, column.Width))))
+			}
+		}
+
+		return record, nil
+	}
+}
+
+// slice returns the part of the current line covered by a column, short lines give short or empty values
+func (r *fixedWidthReader) slice(start int, width int) []rune {
+	if start >= len(r.runes) {
+		return nil
+	}
+	end := start + width
+	if end > len(r.runes) {
+		end = len(r.runes)
+	}
+	return r.runes[start:end]
+}
+
+func (r *fixedWidthReader) InputOffset() int64 {
+	return r.offset
+}
+
+func (r *fixedWidthReader) FieldPos(field int) (line, column int) {
+	if field < 0 || field >= len(r.columns) {
+		return r.line, 0
+	}
+	return r.line, len(string(r.slice(0, r.columns[field].Start))) + 1
+}
+
+type fixedWidthWriter struct {
+	writer  *bufio.Writer
+	columns []FixedWidthColumn
+}
+
+func newFixedWidthWriter(w io.Writer, columns []FixedWidthColumn) *fixedWidthWriter {
+	return &fixedWidthWriter{
+		writer:  bufio.NewWriter(w),
+		columns: columns,
+	}
+}
+
+func (w *fixedWidthWriter) Write(record csvRecord) error {
+	line := []rune{}
+
+	for i, column := range w.columns {
+		var value string
+		if i < len(record) {
+			value = string(record[i])
+		}
+
+		filled, err := column.fill(value)
+		if err != nil {
+			return err
+		}
+
+		// Gaps between columns are filled with spaces
+		for len(line) < column.Start+column.Width {
+			line = append(line, ' ')
+		}
+		copy(line[column.Start:], []rune(filled))
+	}
+
+	if _, err := w.writer.WriteString(string(line)); err != nil {
+		return err
+	}
+	return w.writer.WriteByte('\n')
+}
+
+func (w *fixedWidthWriter) Flush() error {
+	return w.writer.Flush()
+}
@@ -0,0 +1,210 @@
+package csv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type FixedWidth struct {
+	ID      int    `csv:"ID,,,,fixed=0:5,pad=0,align=right"`
+	Name    string `csv:"Name,,,,fixed=5:8"`
+	Balance string `csv:"Balance,,,,fixed=14:6,align=right"`
+	Ignored string
+}
+
+var FixedWidthData = []byte(`00012Bob      000042
+00345Alice     -1000
+`)
+
+var FixedWidthExpected = &[]FixedWidth{
+	{12, "Bob", "000042", ""},
+	{345, "Alice", "-1000", ""},
+}
+
+func TestFixedWidthColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		want    []FixedWidthColumn
+		wantErr bool
+	}{
+		{
+			name: "Tags",
+			v:    &[]FixedWidth{},
+			want: []FixedWidthColumn{
+				{Name: "ID", Start: 0, Width: 5, Pad: '0', Align: AlignRight},
+				{Name: "Name", Start: 5, Width: 8},
+				{Name: "Balance", Start: 14, Width: 6, Align: AlignRight},
+			},
+		},
+		{
+			name: "Order",
+			v: struct {
+				B string `csv:"B,,,,fixed=3:3,keeppad"`
+				A string `csv:"A,,,,fixed=0:3"`
+			}{},
+			want: []FixedWidthColumn{
+				{Name: "A", Start: 0, Width: 3},
+				{Name: "B", Start: 3, Width: 3, KeepPad: true},
+			},
+		},
+		{
+			name: "InvalidPosition",
+			v: struct {
+				A string `csv:"A,,,,fixed=3"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "InvalidWidth",
+			v: struct {
+				A string `csv:"A,,,,fixed=3:0"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "InvalidAlign",
+			v: struct {
+				A string `csv:"A,,,,fixed=0:3,align=center"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "InvalidPad",
+			v: struct {
+				A string `csv:"A,,,,fixed=0:3,pad=ab"`
+			}{},
+			wantErr: true,
+		},
+		{
+			name:    "NotStruct",
+			v:       []int{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FixedWidthColumns(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FixedWidthColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FixedWidthColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_FixedWidth(t *testing.T) {
+	columns, err := FixedWidthColumns(&FixedWidth{})
+	if err != nil {
+		t.Fatalf("FixedWidthColumns() error = %v", err)
+	}
+
+	got := &[]FixedWidth{}
+	if err := Unmarshal(got, &Options{Columns: columns}, FixedWidthData); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, FixedWidthExpected) {
+		t.Errorf("Unmarshal() = %v, want %v", got, FixedWidthExpected)
+	}
+}
+
+func TestMarshal_FixedWidth(t *testing.T) {
+	columns, err := FixedWidthColumns(&FixedWidth{})
+	if err != nil {
+		t.Fatalf("FixedWidthColumns() error = %v", err)
+	}
+
+	got, err := Marshal(FixedWidthExpected, &Options{Columns: columns})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, FixedWidthData) {
+		t.Errorf("Marshal() = %q, want %q", got, FixedWidthData)
+	}
+
+	tooWide := []FixedWidth{{ID: 123456}}
+	if _, err := Marshal(tooWide, &Options{Columns: columns}); err == nil {
+		t.Errorf("Marshal() with a too wide value expected an error")
+	}
+}
+
+func Test_fixedWidthReader(t *testing.T) {
+	columns := []FixedWidthColumn{
+		{Name: "A", Start: 0, Width: 2},
+		{Name: "B", Start: 3, Width: 4, KeepPad: true},
+	}
+
+	reader, err := NewReader(strings.NewReader("ab cd  \r\n#comment\n\nxy z\n"), &Options{Columns: columns, Comment: '#'})
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	if got, want := reader.Headers(), []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader.Headers() = %v, want %v", got, want)
+	}
+
+	want := []struct {
+		line   int
+		fields []string
+	}{
+		{1, []string{"ab", "cd  "}},
+		{4, []string{"xy", "z"}},
+	}
+	for _, tt := range want {
+		record, err := reader.ReadRecord()
+		if err != nil {
+			t.Fatalf("Reader.ReadRecord() error = %v", err)
+		}
+		if record.Line() != tt.line || !reflect.DeepEqual(record.Strings(), tt.fields) {
+			t.Errorf("Reader.ReadRecord() = %v %q, want %v %q", record.Line(), record.Strings(), tt.line, tt.fields)
+		}
+	}
+
+	if line, column := reader.FieldPos(1); line != 4 || column != 4 {
+		t.Errorf("Reader.FieldPos(1) = %v, %v, want 4, 4", line, column)
+	}
+}
+
+type FixedWidthStarred struct {
+	Code string `csv:"Code,,,,fixed=0:4,pad=*"`
+	N    int    `csv:"N,,,,fixed=4:3,pad=0,align=right"`
+}
+
+func TestFixedWidth_PadRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"zero right aligned", &[]FixedWidth{{ID: 0, Name: "Bob", Balance: "0"}}},
+		{"empty left aligned", &[]FixedWidthStarred{{Code: "", N: 0}, {Code: "AB", N: 7}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elem := reflect.TypeOf(tt.want).Elem().Elem()
+			columns, err := FixedWidthColumns(reflect.New(elem).Interface())
+			if err != nil {
+				t.Fatalf("FixedWidthColumns() error = %v", err)
+			}
+
+			data, err := Marshal(tt.want, &Options{Columns: columns})
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			got := reflect.New(reflect.TypeOf(tt.want).Elem()).Interface()
+			if err := Unmarshal(got, &Options{Columns: columns}, data); err != nil {
+				t.Fatalf("Unmarshal(%q) error = %v", data, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal(%q) = %v, want %v", data, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package csv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+/*
+grouping describes a master struct with a detail slice. Exports repeating the master columns on every
+detail row are decoded into one master per key, holding a detail element for each row:
+
+	type Order struct {
+		ID       int         `csv:"OrderID,key"`
+		Customer string      `csv:"Customer"`
+		Lines    []OrderLine `csv:",detail"`
+	}
+
+The key fields are decoded from the first row of a group, and the detail elements from every row.
+*/
+type grouping struct {
+	structType  reflect.Type
+	keys        []int
+	detailIndex []int
+	detailType  reflect.Type
+	detail      *recordDecoder
+}
+
+/*
+getGrouping returns the grouping of the struct type, or nil if it has no detail field.
+*/
+func (d *Decoder) getGrouping(t reflect.Type) (*grouping, error) {
+	g := &grouping{structType: t}
+
+	for i := 0; i < t.NumField(); i++ {
+		field, err := getFieldInfo(t.Field(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, found := field.Options["key"]; found {
+			index, found := d.reader.headerMap[field.Name]
+			if !found {
+				return nil, fmt.Errorf("key field %v missing in header", field.Name)
+			}
+			g.keys = append(g.keys, index)
+		}
+
+		if _, found := field.Options["detail"]; found {
+			if g.detailIndex != nil {
+				return nil, fmt.Errorf("%v has more than one detail field", t)
+			}
+			if err := g.setDetail(field); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if g.detailIndex == nil {
+		return nil, nil
+	}
+	if len(g.keys) == 0 {
+		return nil, fmt.Errorf("%v has a detail field but no key field", t)
+	}
+
+	detailStruct := indirectType(g.detailType)
+	if detail, err := d.getGrouping(detailStruct); err != nil {
+		return nil, err
+	} else if detail != nil {
+		return nil, fmt.Errorf("detail %v can't have detail fields of its own", detailStruct)
+	}
+
+	if !d.generated(detailStruct) {
+		decoder, err := d.recordDecoder(detailStruct)
+		if err != nil {
+			return nil, err
+		}
+		g.detail = decoder
+	}
+
+	return g, nil
+}
+
+func (g *grouping) setDetail(field fieldInfo) error {
+	if field.Type.Kind() != reflect.Slice {
+		return fmt.Errorf("detail field %v must be a slice of structs", field.Name)
+	}
+
+	elemType := field.Type.Elem()
+	if indirectType(elemType).Kind() != reflect.Struct || (elemType.Kind() == reflect.Ptr && elemType.Elem().Kind() == reflect.Ptr) {
+		return fmt.Errorf("detail field %v must be a slice of structs", field.Name)
+	}
+
+	g.detailIndex = field.index
+	g.detailType = elemType
+	return nil
+}
+
+// key returns the key columns of the record joined into one string
+func (g *grouping) key(record *Record) string {
+	parts := make([]string, len(g.keys))
+	for i, index := range g.keys {
+		if index < record.Len() {
+			parts[i] = record.Field(index)
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// blankDetail tells if the record has no values for the detail, like an order without lines
+func (g *grouping) blankDetail(record *Record) bool {
+	if g.detail == nil {
+		return false
+	}
+
+	for _, fieldDecoder := range g.detail.decoders {
+		if fieldDecoder.recordIndex >= 0 && fieldDecoder.recordIndex < record.Len() && len(record.fields[fieldDecoder.recordIndex]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// projection returns the fields used by the master and the detail
+func (g *grouping) projection(master *recordDecoder) columnProjection {
+	if g.detail == nil {
+		return nil
+	}
+
+	projection := master.projection()
+	detail := g.detail.projection()
+	if projection == nil || detail == nil {
+		return nil
+	}
+
+	for i, used := range detail {
+		if !used {
+			continue
+		}
+		for len(projection) <= i {
+			projection = append(projection, false)
+		}
+		projection[i] = true
+	}
+	return projection
+}
+
+/*
+groupReader decodes the groups of a master/detail struct. Contiguous rows with the same key are one group,
+unless the input is unsorted, in which case all the rows are read before the first group is returned.
+*/
+type groupReader struct {
+	decoder  *Decoder
+	grouping *grouping
+	unsorted bool
+
+	// current is the group being read, a pointer to the master
+	current     reflect.Value
+	currentKey  string
+	currentLine int
+
+	// groups are the unsorted groups in order of their first row, once read
+	groups []reflect.Value
+	lines  []int
+	loaded bool
+}
+
+func newGroupReader(decoder *Decoder, grouping *grouping) *groupReader {
+	return &groupReader{decoder: decoder, grouping: grouping, unsorted: decoder.options.Unsorted}
+}
+
+// next returns the next group as elemType, the master struct or a pointer to it
+func (g *groupReader) next(ctx context.Context, elemType reflect.Type) (reflect.Value, error) {
+	var group reflect.Value
+	var err error
+
+	if g.unsorted {
+		group, err = g.nextUnsorted(ctx)
+	} else {
+		group, err = g.nextSorted(ctx)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if elemType.Kind() == reflect.Ptr {
+		return group, nil
+	}
+	return group.Elem(), nil
+}
+
+func (g *groupReader) nextSorted(ctx context.Context) (reflect.Value, error) {
+	for {
+		record, err := g.decoder.readRecord(ctx)
+		if err == io.EOF && g.current.IsValid() {
+			last := g.current
+			g.current = reflect.Value{}
+			return g.finish(last, g.currentLine)
+		}
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		key := g.grouping.key(record)
+		if g.current.IsValid() && key == g.currentKey {
+			if err := g.addDetail(g.current, record); err != nil {
+				return reflect.Value{}, err
+			}
+			continue
+		}
+
+		group, err := g.newGroup(record)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		previous, previousLine := g.current, g.currentLine
+		g.current, g.currentKey, g.currentLine = group, key, record.line
+		if previous.IsValid() {
+			return g.finish(previous, previousLine)
+		}
+	}
+}
+
+func (g *groupReader) nextUnsorted(ctx context.Context) (reflect.Value, error) {
+	if !g.loaded {
+		if err := g.load(ctx); err != nil {
+			return reflect.Value{}, err
+		}
+		g.loaded = true
+	}
+
+	if len(g.groups) == 0 {
+		return reflect.Value{}, io.EOF
+	}
+
+	group, line := g.groups[0], g.lines[0]
+	g.groups, g.lines = g.groups[1:], g.lines[1:]
+	return g.finish(group, line)
+}
+
+// load reads all the records into groups
+func (g *groupReader) load(ctx context.Context) error {
+	groups := map[string]reflect.Value{}
+
+	for {
+		record, err := g.decoder.readRecord(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		key := g.grouping.key(record)
+		if group, found := groups[key]; found {
+			if err := g.addDetail(group, record); err != nil {
+				return err
+			}
+			continue
+		}
+
+		group, err := g.newGroup(record)
+		if err != nil {
+			return err
+		}
+		groups[key] = group
+		g.groups = append(g.groups, group)
+		g.lines = append(g.lines, record.line)
+	}
+}
+
+// newGroup decodes the master and the first detail of a group
+func (g *groupReader) newGroup(record *Record) (reflect.Value, error) {
+	master, err := g.decoder.recordDecoder(g.grouping.structType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	group := reflect.New(g.grouping.structType)
+	if err := master.UnmarshalRecord(structRecord(group.Elem()), record); err != nil {
+		if recordErr, ok := err.(*RecordError); ok {
+			recordErr.Line = record.line
+		}
+		return reflect.Value{}, err
+	}
+
+	if err := g.addDetail(group, record); err != nil {
+		return reflect.Value{}, err
+	}
+	return group, nil
+}
+
+// addDetail appends the detail of the record to the group, and counts the record as decoded
+func (g *groupReader) addDetail(group reflect.Value, record *Record) error {
+	if !g.grouping.blankDetail(record) {
+		elem, err := g.decoder.newElement(g.grouping.detailType, record)
+		if err != nil {
+			return err
+		}
+
+		details := group.Elem().FieldByIndex(g.grouping.detailIndex)
+		details.Set(reflect.Append(details, elem))
+	}
+
+	g.decoder.progress.decoded(record.line, g.decoder.reader.InputOffset())
+	return nil
+}
+
+// finish calls the AfterDecodeCSV hook of the complete group, attributing errors to its first row
+func (g *groupReader) finish(group reflect.Value, line int) (reflect.Value, error) {
+	if err := afterDecode(group.Interface(), &Record{line: line}); err != nil {
+		return reflect.Value{}, err
+	}
+	return group, nil
+}
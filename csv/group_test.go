@@ -0,0 +1,200 @@
+package csv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type orderLine struct {
+	SKU      string `csv:"SKU"`
+	Quantity int    `csv:"Qty"`
+}
+
+type order struct {
+	ID       int         `csv:"OrderID,key"`
+	Customer string      `csv:"Customer"`
+	Lines    []orderLine `csv:",detail"`
+}
+
+type orderPointers struct {
+	ID    int          `csv:"OrderID,key"`
+	Lines []*orderLine `csv:",detail"`
+}
+
+type checkedOrder struct {
+	ID    int         `csv:"OrderID,key"`
+	Lines []orderLine `csv:",detail"`
+}
+
+func (o *checkedOrder) AfterDecodeCSV() error {
+	if len(o.Lines) == 0 {
+		return errors.New("order has no lines")
+	}
+	return nil
+}
+
+const orderFile = `OrderID,Customer,SKU,Qty
+1,Alice,A,2
+1,Alice,B,1
+2,Bob,C,5
+3,Carol,,
+`
+
+func TestUnmarshal_Group(t *testing.T) {
+	var orders []order
+	if err := Unmarshal(&orders, nil, []byte(orderFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []order{
+		{ID: 1, Customer: "Alice", Lines: []orderLine{{"A", 2}, {"B", 1}}},
+		{ID: 2, Customer: "Bob", Lines: []orderLine{{"C", 5}}},
+		{ID: 3, Customer: "Carol"},
+	}
+	if !reflect.DeepEqual(orders, want) {
+		t.Errorf("got %+v, want %+v", orders, want)
+	}
+}
+
+func TestUnmarshal_GroupUnsorted(t *testing.T) {
+	data := `OrderID,Customer,SKU,Qty
+2,Bob,C,5
+1,Alice,A,2
+2,Bob,D,1
+1,Alice,B,1
+`
+
+	tests := []struct {
+		name     string
+		unsorted bool
+		want     []int
+	}{
+		{"sorted", false, []int{2, 1, 2, 1}},
+		{"unsorted", true, []int{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var orders []*orderPointers
+			if err := Unmarshal(&orders, &Options{Unsorted: tt.unsorted}, []byte(data)); err != nil {
+				t.Fatal(err)
+			}
+
+			ids := []int{}
+			lines := 0
+			for _, o := range orders {
+				ids = append(ids, o.ID)
+				lines += len(o.Lines)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("got orders %v, want %v", ids, tt.want)
+			}
+			if lines != 4 {
+				t.Errorf("got %v lines, want 4", lines)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_GroupSingle(t *testing.T) {
+	data := `OrderID,Customer,SKU,Qty
+1,Alice,A,2
+1,Alice,B,1
+`
+
+	var o order
+	if err := Unmarshal(&o, nil, []byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if o.ID != 1 || len(o.Lines) != 2 {
+		t.Errorf("got %+v, want order 1 with 2 lines", o)
+	}
+
+	if err := Unmarshal(&o, nil, []byte(orderFile)); err == nil {
+		t.Error("expected an error for more than one order")
+	}
+}
+
+func TestUnmarshal_GroupHook(t *testing.T) {
+	var orders []checkedOrder
+	err := Unmarshal(&orders, nil, []byte(orderFile))
+
+	var recordErr *RecordError
+	if !errors.As(err, &recordErr) || recordErr.Line != 5 {
+		t.Fatalf("got %v, want a record error on line 5", err)
+	}
+}
+
+func TestUnmarshal_GroupInvalid(t *testing.T) {
+	type noKey struct {
+		Lines []orderLine `csv:",detail"`
+	}
+	type missingKey struct {
+		ID    int         `csv:"Missing,key"`
+		Lines []orderLine `csv:",detail"`
+	}
+	type twoDetails struct {
+		ID    int         `csv:"OrderID,key"`
+		Lines []orderLine `csv:",detail"`
+		More  []orderLine `csv:",detail"`
+	}
+	type notSlice struct {
+		ID   int       `csv:"OrderID,key"`
+		Line orderLine `csv:",detail"`
+	}
+	type nested struct {
+		ID     int     `csv:"OrderID,key"`
+		Orders []order `csv:",detail"`
+	}
+
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{"no key", &[]noKey{}},
+		{"missing key", &[]missingKey{}},
+		{"two details", &[]twoDetails{}},
+		{"not a slice", &[]notSlice{}},
+		{"nested", &[]nested{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unmarshal(tt.v, nil, []byte(orderFile)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestTypedDecoder_Group(t *testing.T) {
+	d, err := NewTypedDecoder[order](strings.NewReader(orderFile), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := []int{}
+	for {
+		o, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts = append(counts, len(o.Lines))
+	}
+
+	if want := []int{2, 1, 0}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("got line counts %v, want %v", counts, want)
+	}
+}
+
+func TestMarshal_Group(t *testing.T) {
+	if _, err := Marshal([]order{{ID: 1}}, nil); err == nil {
+		t.Error("expected an error encoding a detail field")
+	}
+}
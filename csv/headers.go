@@ -1,6 +1,9 @@
 package csv
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 type headerMap map[string]int
 
@@ -14,14 +17,32 @@ func (headers headerList) ToMap() headerMap {
 	return headerMap
 }
 
+// indexHeaders names columns by their zero-based index
+func indexHeaders(count int) headerList {
+	headers := make(headerList, count)
+	for i := range headers {
+		headers[i] = strconv.Itoa(i)
+	}
+	return headers
+}
+
 func getHeaders(r csvReader, headers headerList) (headerMap, error) {
+	headerlist, err := getHeaderList(r, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return headerlist.ToMap(), nil
+}
+
+func getHeaderList(r csvReader, headers headerList) (headerList, error) {
 
 	if r == nil {
 		return nil, fmt.Errorf("reader can't be nil")
 	}
 
 	if headers != nil {
-		return headers.ToMap(), nil
+		return headers, nil
 	}
 
 	headerBytes, err := r.Read()
@@ -29,10 +50,10 @@ func getHeaders(r csvReader, headers headerList) (headerMap, error) {
 		return nil, err
 	}
 
-	headermap := headerMap{}
-	for i, headerByte := range headerBytes {
-		headermap[string(headerByte)] = i
+	headerlist := make(headerList, 0, len(headerBytes))
+	for _, headerByte := range headerBytes {
+		headerlist = append(headerlist, string(headerByte))
 	}
 
-	return headermap, nil
+	return headerlist, nil
 }
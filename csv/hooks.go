@@ -0,0 +1,35 @@
+package csv
+
+/*
+AfterDecoder is implemented by structs that normalize or validate themselves after their fields are decoded.
+
+The Decoder calls AfterDecodeCSV on a pointer to each decoded struct. An error is returned as a *RecordError
+holding the line of the record. With Options.Workers above 1 the hook is called from several goroutines.
+*/
+type AfterDecoder interface {
+	AfterDecodeCSV() error
+}
+
+/*
+BeforeEncoder is implemented by structs that prepare themselves before their fields are encoded.
+
+The Encoder calls BeforeEncodeCSV on a pointer to each struct, or to a copy of it when the struct is not addressable.
+An error stops the encoding before the record is written.
+*/
+type BeforeEncoder interface {
+	BeforeEncodeCSV() error
+}
+
+// afterDecode calls the AfterDecodeCSV hook of v, if it has one
+func afterDecode(v interface{}, record *Record) error {
+	hook, ok := v.(AfterDecoder)
+	if !ok {
+		return nil
+	}
+
+	if err := hook.AfterDecodeCSV(); err != nil {
+		return &RecordError{Line: record.line, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Hooked struct {
+	Name  string
+	Email string
+}
+
+func (h *Hooked) AfterDecodeCSV() error {
+	if h.Email == "" {
+		return fmt.Errorf("%v has no email", h.Name)
+	}
+	h.Email = strings.ToLower(h.Email)
+	return nil
+}
+
+func (h *Hooked) BeforeEncodeCSV() error {
+	if h.Name == "" {
+		return fmt.Errorf("name is missing")
+	}
+	h.Email = strings.ToUpper(h.Email)
+	return nil
+}
+
+func TestDecoder_AfterDecodeCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		options  *Options
+		want     []Hooked
+		wantLine int
+	}{
+		{
+			name: "normalized",
+			data: "Name,Email\nBob,Bob@Example.com\nAlice,ALICE@example.com\n",
+			want: []Hooked{{"Bob", "bob@example.com"}, {"Alice", "alice@example.com"}},
+		},
+		{
+			name:     "error",
+			data:     "Name,Email\nBob,bob@example.com\nAlice,\n",
+			wantLine: 3,
+		},
+		{
+			name:     "parallel error",
+			data:     "Name,Email\nBob,bob@example.com\nAlice,\nEve,\n",
+			options:  &Options{Workers: 4},
+			wantLine: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []Hooked{}
+			err := Unmarshal(&got, tt.options, []byte(tt.data))
+
+			if tt.wantLine == 0 {
+				if err != nil {
+					t.Fatalf("Unmarshal() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+				}
+				return
+			}
+
+			var recordErr *RecordError
+			if !errors.As(err, &recordErr) || recordErr.Line != tt.wantLine {
+				t.Errorf("Unmarshal() error = %v, want a RecordError on line %v", err, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestTypedDecoder_AfterDecodeCSV(t *testing.T) {
+	d, err := NewTypedDecoder[Hooked](strings.NewReader("Name,Email\nBob,BOB@example.com\nAlice,\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := d.Next(); err != nil || got.Email != "bob@example.com" {
+		t.Errorf("Next() = %v, %v, want bob@example.com", got, err)
+	}
+
+	var recordErr *RecordError
+	if _, err := d.Next(); !errors.As(err, &recordErr) || recordErr.Line != 3 {
+		t.Errorf("Next() error = %v, want a RecordError on line 3", err)
+	}
+}
+
+func TestEncoder_BeforeEncodeCSV(t *testing.T) {
+	records := []Hooked{{"Bob", "bob@example.com"}}
+
+	got, err := Marshal(records, nil)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := "Name,Email\nBob,BOB@EXAMPLE.COM\n"; string(got) != want {
+		t.Errorf("Marshal() = %q, want %q", got, want)
+	}
+
+	// An error from the hook stops the encoding
+	if _, err := Marshal(Hooked{Email: "eve@example.com"}, nil); err == nil {
+		t.Errorf("Marshal() expected an error")
+	}
+}
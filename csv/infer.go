@@ -0,0 +1,188 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+An InferRule guesses the value of a cell read into an interface{}. It returns false if the text is not of its type.
+
+The rules in Options.Infer are tried in order, and the first match is stored. A text no rule matches is stored as a
+string, and a blank cell as nil.
+*/
+type InferRule func(text string) (interface{}, bool)
+
+/*
+InferInt reads decimal integers as int64. Numbers with leading zeros, like 007, are left for the next rule,
+as they are usually codes rather than numbers.
+*/
+var InferInt InferRule = func(text string) (interface{}, bool) {
+	if !isDecimal(text, false) {
+		return nil, false
+	}
+
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return n, true
+}
+
+/*
+InferFloat reads decimal numbers with a fraction or an exponent as float64, like 1.5 or 2e3.
+Leading zeros are left for the next rule like for InferInt, and so are words like NaN and Inf.
+*/
+var InferFloat InferRule = func(text string) (interface{}, bool) {
+	if !isDecimal(text, true) {
+		return nil, false
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
+/*
+InferBool reads true and false as bool, in lower, upper or title case.
+*/
+var InferBool InferRule = func(text string) (interface{}, bool) {
+	switch text {
+	case "true", "TRUE", "True":
+		return true, true
+	case "false", "FALSE", "False":
+		return false, true
+	}
+	return nil, false
+}
+
+/*
+InferTime returns a rule reading time.Time in the first of the layouts that matches.
+*/
+func InferTime(layouts ...string) InferRule {
+	return func(text string) (interface{}, bool) {
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, true
+			}
+		}
+		return nil, false
+	}
+}
+
+/*
+DefaultInference reads integers, floats, booleans and times, in that order. Times are RFC 3339,
+time.DateTime or time.DateOnly.
+*/
+var DefaultInference = []InferRule{
+	InferInt,
+	InferFloat,
+	InferBool,
+	InferTime(time.RFC3339Nano, time.DateTime, time.DateOnly),
+}
+
+// isDecimal tells if the text is a number without leading zeros, with a fraction or an exponent if fraction is true
+func isDecimal(text string, fraction bool) bool {
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "-"), "+")
+
+	mantissa, exponent, hasExponent := strings.Cut(strings.ToLower(text), "e")
+	whole, decimals, hasDecimals := strings.Cut(mantissa, ".")
+
+	if !digits(whole) || len(whole) > 1 && whole[0] == '0' {
+		return false
+	}
+	if !fraction {
+		return !hasDecimals && !hasExponent
+	}
+
+	if hasDecimals && !digits(decimals) {
+		return false
+	}
+	if hasExponent && !digits(strings.TrimPrefix(strings.TrimPrefix(exponent, "-"), "+")) {
+		return false
+	}
+	return hasDecimals || hasExponent
+}
+
+func digits(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, c := range text {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// inference is the converter of interface{} fields when Options.Infer is set
+type inference []InferRule
+
+func (rules inference) Unmarshal(v interface{}, text []byte) error {
+	target := reflect.ValueOf(v).Elem()
+
+	value := rules.infer(string(text))
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	target.Set(reflect.ValueOf(value))
+	return nil
+}
+
+func (rules inference) infer(text string) interface{} {
+	if text == "" {
+		return nil
+	}
+
+	for _, rule := range rules {
+		if value, ok := rule(text); ok {
+			return value
+		}
+	}
+	return text
+}
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+var mapType = reflect.TypeOf(map[string]interface{}{})
+
+// newMap returns a map of the headers to the inferred values of the record
+func (d *Decoder) newMap(record *Record) reflect.Value {
+	rules := inference(d.options.Infer)
+	if rules == nil {
+		rules = DefaultInference
+	}
+
+	values := make(map[string]interface{}, len(d.reader.headers))
+	for i, header := range d.reader.headers {
+		if i < record.Len() {
+			values[header] = rules.infer(string(record.fields[i]))
+		}
+	}
+
+	return reflect.ValueOf(values)
+}
+
+// verifyMap checks that a map target is a map[string]interface{}, and that no header would overwrite another
+func verifyMap(t reflect.Type, container string, headers []string) error {
+	if t != mapType {
+		return fmt.Errorf("can't decode into %v element %v, want map[string]interface{}", container, t)
+	}
+
+	seen := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		if seen[header] {
+			return fmt.Errorf("can't decode into %v element %v, duplicate header %q", container, t, header)
+		}
+		seen[header] = true
+	}
+	return nil
+}
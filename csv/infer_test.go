@@ -0,0 +1,117 @@
+package csv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDefaultInference(t *testing.T) {
+	tests := []struct {
+		text string
+		want interface{}
+	}{
+		{"", nil},
+		{"42", int64(42)},
+		{"-7", int64(-7)},
+		{"0", int64(0)},
+		{"007", "007"},
+		{"1.5", 1.5},
+		{"-2e3", -2000.0},
+		{"0.25", 0.25},
+		{"01.5", "01.5"},
+		{"NaN", "NaN"},
+		{"1.", "1."},
+		{"99999999999999999999", "99999999999999999999"},
+		{"true", true},
+		{"FALSE", false},
+		{"yes", "yes"},
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-01 12:30:00", time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)},
+		{"abc", "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := inference(DefaultInference).infer(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_InferField(t *testing.T) {
+	type row struct {
+		Name  string
+		Value interface{}
+	}
+
+	data := []byte("Name,Value\na,abc\nb,007\nc,12\nd,\n")
+
+	tests := []struct {
+		name    string
+		options *Options
+		want    []interface{}
+		wantErr bool
+	}{
+		{"json", nil, nil, true},
+		{"default", &Options{Infer: DefaultInference}, []interface{}{"abc", "007", int64(12), nil}, false},
+		{"strings only", &Options{Infer: []InferRule{}}, []interface{}{"abc", "007", "12", nil}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []row
+			err := Unmarshal(&rows, tt.options, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := []interface{}{}
+			for _, r := range rows {
+				got = append(got, r.Value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Map(t *testing.T) {
+	data := []byte("ID,Name,Active,Score\n1,Alice,true,9.5\n2,Bob,false\n")
+
+	var rows []map[string]interface{}
+	if err := Unmarshal(&rows, &Options{FieldsPerRecord: -1}, data); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []map[string]interface{}{
+		{"ID": int64(1), "Name": "Alice", "Active": true, "Score": 9.5},
+		{"ID": int64(2), "Name": "Bob", "Active": false},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %v, want %v", rows, want)
+	}
+
+	var single map[string]interface{}
+	if err := Unmarshal(&single, nil, []byte("ID\n7\n")); err != nil {
+		t.Fatal(err)
+	}
+	if single["ID"] != int64(7) {
+		t.Errorf("got %v, want map[ID:7]", single)
+	}
+
+	var wrong []map[string]string
+	if err := Unmarshal(&wrong, nil, data); err == nil {
+		t.Error("expected an error for map[string]string")
+	}
+
+	var duplicate []map[string]interface{}
+	if err := Unmarshal(&duplicate, nil, []byte("ID,Name,ID\n1,Alice,2\n")); err == nil {
+		t.Error("expected an error for a duplicate header")
+	}
+}
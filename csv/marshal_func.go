@@ -0,0 +1,13 @@
+package csv
+
+/*
+MarshalFunc is the method implemented by an object that can marshal v into a textual representation.
+
+MarshalFunc must produce a form that UnmarshalFunc can decode.
+*/
+type MarshalFunc func(v interface{}) ([]byte, error)
+
+// Marshal calls f, so registered functions are converters like the native ones
+func (f MarshalFunc) Marshal(v interface{}) ([]byte, error) {
+	return f(v)
+}
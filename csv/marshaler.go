@@ -0,0 +1,22 @@
+package csv
+
+/*
+Unmarshaler is the interface implemented by types that can unmarshal a csv field of themselves.
+
+UnmarshalCSV is used before encoding.TextUnmarshaler, so a type can be read differently from csv than from its text form.
+UnmarshalCSV must copy the text if it wishes to retain the text after returning.
+Fields holding a pointer to such a type get a new value for each cell, and stay nil for blank cells.
+*/
+type Unmarshaler interface {
+	UnmarshalCSV(text []byte) error
+}
+
+/*
+Marshaler is the interface implemented by types that can marshal themselves into a csv field.
+
+MarshalCSV is used before encoding.TextMarshaler and must produce a form that UnmarshalCSV can decode.
+Nil pointers to such a type are written as blank cells.
+*/
+type Marshaler interface {
+	MarshalCSV() ([]byte, error)
+}
@@ -0,0 +1,126 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// grade is a letter in csv files and a number in text, to tell which of the methods is used
+type grade int
+
+func (g *grade) UnmarshalCSV(text []byte) error {
+	if len(text) != 1 || text[0] < 'A' || text[0] > 'F' {
+		return fmt.Errorf("invalid grade %q", text)
+	}
+	*g = grade(text[0]-'A') + 1
+	return nil
+}
+
+func (g *grade) MarshalCSV() ([]byte, error) {
+	if *g < 1 || *g > 6 {
+		return nil, fmt.Errorf("invalid grade %d", *g)
+	}
+	return []byte{byte('A' + *g - 1)}, nil
+}
+
+func (g *grade) UnmarshalText(text []byte) error {
+	n, err := strconv.Atoi(string(text))
+	*g = grade(n)
+	return err
+}
+
+func (g grade) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(g))), nil
+}
+
+type graded struct {
+	Name  string
+	Grade grade
+	Best  *grade
+}
+
+// BeforeEncodeCSV rejects students without a name, to check that the hook runs before the fields are marshalled
+func (g *graded) BeforeEncodeCSV() error {
+	if g.Name == "" {
+		return fmt.Errorf("name is missing")
+	}
+	return nil
+}
+
+func newGrade(g grade) *grade {
+	return &g
+}
+
+func TestUnmarshal_Unmarshaler(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []graded
+		wantErr bool
+	}{
+		{name: "before text", data: "Name,Grade,Best\nBob,A,B\n", want: []graded{{"Bob", 1, newGrade(2)}}},
+		{name: "blank pointer", data: "Name,Grade,Best\nBob,C,\n", want: []graded{{"Bob", 3, nil}}},
+		{name: "text form", data: "Name,Grade,Best\nBob,1,\n", wantErr: true},
+		{name: "invalid pointer", data: "Name,Grade,Best\nBob,A,X\n", wantErr: true},
+		{name: "blank value", data: "Name,Grade,Best\nBob,,A\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []graded{}
+			err := Unmarshal(&got, nil, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshal_Marshaler(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       []graded
+		want    string
+		wantErr bool
+	}{
+		{name: "before text", v: []graded{{"Bob", 1, newGrade(2)}}, want: "Name,Grade,Best\nBob,A,B\n"},
+		{name: "nil pointer", v: []graded{{"Bob", 3, nil}}, want: "Name,Grade,Best\nBob,C,\n"},
+		{name: "invalid value", v: []graded{{"Bob", 7, nil}}, wantErr: true},
+		{name: "invalid pointer", v: []graded{{"Bob", 1, newGrade(0)}}, wantErr: true},
+		{name: "hook", v: []graded{{"", 1, nil}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.v, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Marshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("Marshal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshaler_RoundTrip(t *testing.T) {
+	want := []graded{{"Bob", 1, newGrade(6)}, {"Alice", 4, nil}}
+
+	data, err := Marshal(want, nil)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	got := []graded{}
+	if err := Unmarshal(&got, nil, data); err != nil {
+		t.Fatalf("Unmarshal(%q) error = %v", data, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal(%q) = %v, want %v", data, got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// metaField is a field filled with the provenance of the record instead of a column, see the meta tag option
+type metaField struct {
+	kind        string
+	structIndex []int
+}
+
+func newMetaField(field fieldInfo) (metaField, error) {
+	kind := field.Options["meta"]
+	t := indirectType(field.Type)
+
+	switch kind {
+	case "line":
+		if !isNumber(t) || t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 {
+			return metaField{}, fmt.Errorf("meta=line on field %v needs an integer, not %v", field.Name, field.Type)
+		}
+	case "raw", "file":
+		if t.Kind() != reflect.String {
+			return metaField{}, fmt.Errorf("meta=%v on field %v needs a string, not %v", kind, field.Name, field.Type)
+		}
+	default:
+		return metaField{}, fmt.Errorf("unknown meta %q on field %v, want line, raw or file", kind, field.Name)
+	}
+
+	return metaField{kind: kind, structIndex: field.index}, nil
+}
+
+func (m metaField) decode(object structRecord, record *Record) {
+	value := reflect.ValueOf(object.GetField(m.structIndex)).Elem()
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+
+	switch m.kind {
+	case "line":
+		if value.CanInt() {
+			value.SetInt(int64(record.line))
+		} else {
+			value.SetUint(uint64(record.line))
+		}
+	case "raw":
+		value.SetString(record.Raw())
+	case "file":
+		value.SetString(record.file)
+	}
+}
@@ -0,0 +1,134 @@
+package csv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type auditedRow struct {
+	Name string
+	Line int    `csv:",meta=line"`
+	Raw  string `csv:",meta=raw"`
+	File string `csv:",meta=file"`
+}
+
+func TestUnmarshal_Meta(t *testing.T) {
+	data := `Name,Age
+Alice,30
+"Bob",40
+`
+
+	tests := []struct {
+		name    string
+		options *Options
+		want    []auditedRow
+	}{
+		{
+			"comma",
+			&Options{FileName: "people.csv"},
+			[]auditedRow{
+				{Name: "Alice", Line: 2, Raw: "Alice,30", File: "people.csv"},
+				{Name: "Bob", Line: 3, Raw: "Bob,40", File: "people.csv"},
+			},
+		},
+		{
+			"semicolon",
+			&Options{Comma: ';'},
+			[]auditedRow{
+				{Name: "Alice", Line: 2, Raw: "Alice;30"},
+				{Name: "Bob", Line: 3, Raw: "Bob;40"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := data
+			if tt.options.Comma != 0 {
+				input = strings.ReplaceAll(data, ",", string(tt.options.Comma))
+			}
+
+			var rows []auditedRow
+			if err := Unmarshal(&rows, tt.options, []byte(input)); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("got %+v, want %+v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_MetaPointer(t *testing.T) {
+	type row struct {
+		Name string
+		Line *uint `csv:",meta=line"`
+	}
+
+	var rows []row
+	if err := Unmarshal(&rows, nil, []byte("Name\nAlice\n")); err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].Line == nil || *rows[0].Line != 2 {
+		t.Errorf("got line %v, want 2", rows[0].Line)
+	}
+}
+
+func TestUnmarshal_MetaInvalid(t *testing.T) {
+	type stringLine struct {
+		Line string `csv:",meta=line"`
+	}
+	type intRaw struct {
+		Raw int `csv:",meta=raw"`
+	}
+	type unknown struct {
+		Column string `csv:",meta=column"`
+	}
+
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{"line into string", &[]stringLine{}},
+		{"raw into int", &[]intRaw{}},
+		{"unknown meta", &[]unknown{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unmarshal(tt.v, nil, []byte("Name\nAlice\n")); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestMarshal_Meta(t *testing.T) {
+	data, err := Marshal([]auditedRow{{Name: "Alice", Line: 2, Raw: "Alice", File: "people.csv"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "Name\nAlice\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
+func TestRecord_Raw(t *testing.T) {
+	decoder, err := NewDecoder(strings.NewReader("A|B|C\n1|2|3\n"), &Options{Comma: '|', FileName: "in.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := decoder.Reader().ReadRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := record.Raw(); got != "1|2|3" {
+		t.Errorf("got raw %q, want %q", got, "1|2|3")
+	}
+	if got := record.File(); got != "in.csv" {
+		t.Errorf("got file %q, want %q", got, "in.csv")
+	}
+}
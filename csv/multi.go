@@ -0,0 +1,264 @@
+package csv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+)
+
+/*
+A MultiDecoder reads files mixing kinds of records, like header, detail and trailer rows.
+A discriminator column holds the kind of each record, and each kind is decoded into its own struct type.
+*/
+type MultiDecoder struct {
+	decoder       *Decoder
+	discriminator string
+	column        int
+	kinds         map[string]*recordKind
+}
+
+// recordKind is a struct type registered for a value of the discriminator
+type recordKind struct {
+	structType reflect.Type
+	pointer    bool
+	headers    headerMap
+	decoder    *recordDecoder
+	handler    func(v interface{}) error
+}
+
+/*
+NewMultiDecoder returns a decoder of the records in r, with the kind of each record in the column named discriminator.
+
+Records of different kinds usually have different lengths, so a FieldsPerRecord of 0 is treated as -1.
+Files without a header row need Options.NoHeader, which names the columns by their index, or Options.Headers.
+See NewDecoder for the meaning of the other options.
+*/
+func NewMultiDecoder(r io.Reader, discriminator string, options *Options) (*MultiDecoder, error) {
+	readerOptions := Options{FieldsPerRecord: -1}
+	if options != nil {
+		readerOptions = *options
+		if readerOptions.FieldsPerRecord == 0 {
+			readerOptions.FieldsPerRecord = -1
+		}
+	}
+
+	decoder, err := NewDecoder(r, &readerOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	column, found := decoder.reader.headerMap[discriminator]
+	if !found {
+		return nil, fmt.Errorf("discriminator column %v missing in header", discriminator)
+	}
+
+	return &MultiDecoder{
+		decoder:       decoder,
+		discriminator: discriminator,
+		column:        column,
+		kinds:         map[string]*recordKind{},
+	}, nil
+}
+
+/*
+Register decodes the records with the given discriminator value into the type of prototype, a struct or a pointer
+to a struct. The records are returned the same way, as structs or as pointers to new structs.
+
+headers names the columns of the records of this kind by position. If headers is nil the headers of the file are used.
+*/
+func (m *MultiDecoder) Register(value string, prototype interface{}, headers []string) error {
+	if _, found := m.kinds[value]; found {
+		return fmt.Errorf("record type %q is already registered", value)
+	}
+
+	t := reflect.TypeOf(prototype)
+	kind := &recordKind{headers: m.decoder.reader.headerMap}
+	if t != nil && t.Kind() == reflect.Ptr {
+		kind.pointer = true
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("can't decode record type %q into %T, want a struct or a pointer to a struct", value, prototype)
+	}
+	kind.structType = t
+
+	if headers != nil {
+		kind.headers = headerList(headers).ToMap()
+	}
+
+	if err := m.prepareKind(kind); err != nil {
+		return err
+	}
+
+	m.kinds[value] = kind
+	return nil
+}
+
+/*
+Handle registers T for the records with the given discriminator value, like Register,
+and calls handler with each of them when the records are read by Run.
+*/
+func Handle[T any](m *MultiDecoder, value string, headers []string, handler func(T) error) error {
+	var prototype T
+	if err := m.Register(value, prototype, headers); err != nil {
+		return err
+	}
+
+	m.kinds[value].handler = func(v interface{}) error {
+		return handler(v.(T))
+	}
+	return nil
+}
+
+/*
+RegisterEnum makes the decoder read fields of the type of enum by name, see Decoder.RegisterEnum.
+
+Register converts the defaults of the kind, so an enum a default is named in must be registered before the kind,
+or be given in Options.Enums.
+*/
+func (m *MultiDecoder) RegisterEnum(enum *Enum) error {
+	m.decoder.RegisterEnum(enum)
+	return m.prepareKinds()
+}
+
+/*
+RegisterType makes the decoder read fields of the type of v with unmarshal, see Decoder.RegisterType.
+Like for RegisterEnum, types converting defaults must be registered before the kinds, or be given in Options.Types.
+*/
+func (m *MultiDecoder) RegisterType(v interface{}, unmarshal UnmarshalFunc) error {
+	m.decoder.RegisterType(v, unmarshal)
+	return m.prepareKinds()
+}
+
+/*
+RegisterTransform adds a named transform for the transform tag option, see Decoder.RegisterTransform.
+
+Register checks the tags of the kind, so a transform must be registered before the kinds naming it,
+or be given in Options.Transforms.
+*/
+func (m *MultiDecoder) RegisterTransform(name string, transform Transform) error {
+	m.decoder.RegisterTransform(name, transform)
+	return m.prepareKinds()
+}
+
+// prepareKinds creates the record decoders of the registered kinds again, with the current converters
+func (m *MultiDecoder) prepareKinds() error {
+	for _, kind := range m.kinds {
+		if err := m.prepareKind(kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareKind creates the record decoder of the kind, unless it has a generated decoder
+func (m *MultiDecoder) prepareKind(kind *recordKind) error {
+	if m.decoder.generated(kind.structType) {
+		kind.decoder = nil
+		return nil
+	}
+
+	decoder, err := newRecordDecoder(structType{kind.structType}, kind.headers, m.decoder.converters, m.decoder.transforms)
+	if err != nil {
+		return err
+	}
+
+	kind.decoder = decoder
+	return nil
+}
+
+/*
+Next reads the next record and returns it decoded into the type registered for its kind.
+At the end of the input Next returns io.EOF.
+*/
+func (m *MultiDecoder) Next() (interface{}, error) {
+	v, _, err := m.next(context.Background())
+	return v, err
+}
+
+func (m *MultiDecoder) next(ctx context.Context) (interface{}, *recordKind, error) {
+	record, err := m.decoder.readRecord(ctx)
+	if err == io.EOF {
+		m.decoder.progress.done()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value := ""
+	if m.column < record.Len() {
+		value = record.Field(m.column)
+	}
+
+	kind, found := m.kinds[value]
+	if !found {
+		return nil, nil, &RecordError{Line: record.line, Column: m.discriminator, Err: fmt.Errorf("unknown record type %q", value)}
+	}
+
+	// The fields are named by the headers of the kind
+	kindRecord := *record
+	kindRecord.headers = kind.headers
+
+	elem := reflect.New(kind.structType)
+	if err := unmarshalStruct(kind.decoder, elem.Elem(), &kindRecord); err != nil {
+		return nil, nil, err
+	}
+	m.decoder.progress.decoded(record.line, m.decoder.reader.InputOffset())
+
+	if kind.pointer {
+		return elem.Interface(), kind, nil
+	}
+	return elem.Elem().Interface(), kind, nil
+}
+
+/*
+All returns an iterator over the remaining records.
+
+The iteration stops after the first error, which is yielded together with a nil value.
+*/
+func (m *MultiDecoder) All() iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		for {
+			v, err := m.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+/*
+Run reads the remaining records and calls the handler registered with Handle for each of them.
+Records of kinds registered without a handler are skipped. Run stops at the first error, returned by
+a handler or by the decoding, or when ctx is done.
+*/
+func (m *MultiDecoder) Run(ctx context.Context) error {
+	for {
+		v, kind, err := m.next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if kind.handler == nil {
+			continue
+		}
+		if err := kind.handler(v); err != nil {
+			return err
+		}
+	}
+}
+
+/*
+Reader returns the Reader of the decoder.
+*/
+func (m *MultiDecoder) Reader() *Reader {
+	return m.decoder.reader
+}
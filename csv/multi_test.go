@@ -0,0 +1,161 @@
+package csv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type batchHeader struct {
+	Date   string
+	Sender string
+}
+
+type batchDetail struct {
+	ID    int
+	Item  string
+	Price float64
+}
+
+type batchTrailer struct {
+	Count int
+}
+
+const batchFile = `H,20240101,ACME
+D,1,Widget,2.5
+D,2,Gadget,3
+T,2
+`
+
+func newBatchDecoder(t *testing.T, data string) *MultiDecoder {
+	t.Helper()
+
+	m, err := NewMultiDecoder(strings.NewReader(data), "0", &Options{NoHeader: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registrations := []struct {
+		value     string
+		prototype interface{}
+		headers   []string
+	}{
+		{"H", batchHeader{}, []string{"Type", "Date", "Sender"}},
+		{"D", &batchDetail{}, []string{"Type", "ID", "Item", "Price"}},
+		{"T", batchTrailer{}, []string{"Type", "Count"}},
+	}
+	for _, r := range registrations {
+		if err := m.Register(r.value, r.prototype, r.headers); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return m
+}
+
+func TestMultiDecoder_All(t *testing.T) {
+	m := newBatchDecoder(t, batchFile)
+
+	got := []interface{}{}
+	for v, err := range m.All() {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		got = append(got, v)
+	}
+
+	want := []interface{}{
+		batchHeader{"20240101", "ACME"},
+		&batchDetail{1, "Widget", 2.5},
+		&batchDetail{2, "Gadget", 3},
+		batchTrailer{2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+}
+
+func TestMultiDecoder_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		line   int
+		column string
+	}{
+		{name: "unknown type", data: "H,20240101,ACME\nX,1\n", line: 2, column: "0"},
+		{name: "invalid field", data: "H,20240101,ACME\nD,one,Widget,2.5\n"},
+		{name: "short record", data: "D,1,Widget\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newBatchDecoder(t, tt.data)
+
+			var err error
+			for _, err = range m.All() {
+			}
+
+			if err == nil {
+				t.Fatalf("All() expected an error")
+			}
+			var recordErr *RecordError
+			if tt.line > 0 && (!errors.As(err, &recordErr) || recordErr.Line != tt.line || recordErr.Column != tt.column) {
+				t.Errorf("All() error = %v, want a RecordError on line %v, column %v", err, tt.line, tt.column)
+			}
+		})
+	}
+
+	if _, err := NewMultiDecoder(strings.NewReader("Type,A\n"), "Kind", nil); err == nil {
+		t.Errorf("NewMultiDecoder() expected an error for a missing discriminator")
+	}
+
+	m := newBatchDecoder(t, batchFile)
+	if err := m.Register("H", batchHeader{}, nil); err == nil {
+		t.Errorf("Register() expected an error for a registered value")
+	}
+	if err := m.Register("X", 1, nil); err == nil {
+		t.Errorf("Register() expected an error for a non-struct")
+	}
+}
+
+func TestMultiDecoder_Run(t *testing.T) {
+	m, err := NewMultiDecoder(strings.NewReader(batchFile), "0", &Options{NoHeader: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := 0.0
+	count := 0
+	if err := Handle(m, "D", []string{"Type", "ID", "Item", "Price"}, func(d batchDetail) error {
+		total += d.Price
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Handle(m, "T", []string{"Type", "Count"}, func(tr *batchTrailer) error {
+		count = tr.Count
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register("H", batchHeader{}, []string{"Type", "Date", "Sender"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if total != 5.5 || count != 2 {
+		t.Errorf("Run() handled total %v and count %v, want 5.5 and 2", total, count)
+	}
+
+	// A handler error stops the run
+	m = newBatchDecoder(t, batchFile)
+	m.kinds["T"].handler = func(interface{}) error { return fmt.Errorf("bad trailer") }
+	if err := m.Run(context.Background()); err == nil || err.Error() != "bad trailer" {
+		t.Errorf("Run() error = %v, want bad trailer", err)
+	}
+}
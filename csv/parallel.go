@@ -0,0 +1,130 @@
+package csv
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// parallelBatchSize is the number of records handed to a worker at a time, keeping the channel overhead low
+const parallelBatchSize = 64
+
+type parallelJob struct {
+	records []*Record
+	offsets []int64
+	elems   []reflect.Value
+	err     error
+	done    chan struct{}
+}
+
+func (job *parallelJob) run(d *Decoder, elemType reflect.Type) {
+	defer close(job.done)
+
+	for _, record := range job.records {
+		elem, err := d.newElement(elemType, record)
+		if err != nil {
+			job.err = err
+			return
+		}
+		job.elems = append(job.elems, elem)
+	}
+}
+
+// decodeParallel reads the records in the calling goroutine and converts them in Options.Workers goroutines.
+// The elements are passed to add in input order, and the error of the first failing record is returned,
+// no matter which worker finishes first.
+func (d *Decoder) decodeParallel(ctx context.Context, elemType reflect.Type, add func(reflect.Value) error) error {
+	workers := d.options.Workers
+
+	work := make(chan *parallelJob)
+	ordered := make(chan *parallelJob, 2*workers)
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range work {
+				job.run(d, elemType)
+			}
+		}()
+	}
+
+	// The collector waits for the jobs in the order they were read
+	collected := make(chan error, 1)
+	go func() {
+		var first error
+		for job := range ordered {
+			<-job.done
+			if first != nil {
+				continue
+			}
+
+			// Elements decoded before a failing record are kept, like when decoding sequentially
+			for i, elem := range job.elems {
+				if first = add(elem); first != nil {
+					break
+				}
+				d.progress.decoded(job.records[i].line, job.offsets[i])
+			}
+			if first == nil {
+				first = job.err
+			}
+			if first != nil {
+				close(stop)
+			}
+		}
+		collected <- first
+	}()
+
+	dispatch := func(job *parallelJob) {
+		if len(job.records) > 0 {
+			ordered <- job
+			work <- job
+		}
+	}
+
+	var readErr error
+	job := &parallelJob{done: make(chan struct{})}
+read:
+	for {
+		select {
+		case <-stop:
+			break read
+		default:
+		}
+
+		record, err := d.readRecord(ctx)
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			break
+		}
+
+		job.records = append(job.records, record)
+		job.offsets = append(job.offsets, d.reader.InputOffset())
+		if len(job.records) == parallelBatchSize {
+			dispatch(job)
+			job = &parallelJob{done: make(chan struct{})}
+		}
+	}
+	dispatch(job)
+
+	close(work)
+	close(ordered)
+	wg.Wait()
+
+	// Errors of records read earlier take precedence over the read error
+	if err := <-collected; err != nil {
+		return err
+	}
+	if readErr != nil {
+		return readErr
+	}
+
+	d.progress.done()
+	return nil
+}
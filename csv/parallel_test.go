@@ -0,0 +1,107 @@
+package csv
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type ParallelCode string
+
+func (c *ParallelCode) UnmarshalText(text []byte) error {
+	if bytes.HasPrefix(text, []byte("bad")) {
+		return fmt.Errorf("invalid code %s", text)
+	}
+	*c = ParallelCode(text)
+	return nil
+}
+
+type ParallelRecord struct {
+	ID    int
+	Code  ParallelCode
+	Score float64
+	Tags  []string
+}
+
+func parallelCSV(records int, bad ...int) []byte {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("ID,Code,Score,Tags\n")
+	for i := 0; i < records; i++ {
+		code := fmt.Sprintf("code-%v", i)
+		for _, b := range bad {
+			if b == i {
+				code = fmt.Sprintf("bad-%v", i)
+			}
+		}
+		fmt.Fprintf(buffer, "%v,%v,%v.5,\"[\"\"a\"\",\"\"b%v\"\"]\"\n", i, code, i, i)
+	}
+	return buffer.Bytes()
+}
+
+func TestDecoder_DecodeParallel(t *testing.T) {
+	data := parallelCSV(500)
+
+	want := &[]ParallelRecord{}
+	if err := Unmarshal(want, nil, data); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	for _, workers := range []int{2, 3, 8} {
+		t.Run(fmt.Sprintf("Workers%v", workers), func(t *testing.T) {
+			got := &[]*ParallelRecord{}
+			if err := Unmarshal(got, &Options{Workers: workers}, data); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if len(*got) != len(*want) {
+				t.Fatalf("Unmarshal() decoded %v records, want %v", len(*got), len(*want))
+			}
+			for i, record := range *got {
+				if !reflect.DeepEqual(*record, (*want)[i]) {
+					t.Fatalf("Unmarshal() record %v = %v, want %v", i, *record, (*want)[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_DecodeParallelFirstError(t *testing.T) {
+	data := parallelCSV(500, 120, 121, 300)
+
+	for i := 0; i < 20; i++ {
+		got := &[]ParallelRecord{{ID: -1}}
+		err := Unmarshal(got, &Options{Workers: 4}, data)
+		if err == nil || err.Error() != "invalid code bad-120" {
+			t.Fatalf("Unmarshal() error = %v, want invalid code bad-120", err)
+		}
+		if len(*got) != 1 {
+			t.Fatalf("Unmarshal() changed the slice on error")
+		}
+	}
+}
+
+func TestDecoder_DecodeParallelArray(t *testing.T) {
+	got := &[2]ParallelRecord{}
+	err := Unmarshal(got, &Options{Workers: 4}, parallelCSV(3))
+	if err == nil || !strings.HasPrefix(err.Error(), "can't decode more than 2 records") {
+		t.Errorf("Unmarshal() error = %v, want too many records", err)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data := parallelCSV(10000)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("Workers%v", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				records := []ParallelRecord{}
+				if err := Unmarshal(&records, &Options{Workers: workers}, data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
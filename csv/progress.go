@@ -0,0 +1,60 @@
+package csv
+
+/*
+Progress describes how far a Decoder has come through its input.
+*/
+type Progress struct {
+	// Records is the number of records decoded
+	Records int
+
+	// Bytes is the number of bytes of the input consumed, or 0 if it is unknown
+	Bytes int64
+
+	// Line is the line on which the last record decoded starts, or 0 if it is unknown
+	Line int
+}
+
+const defaultProgressInterval = 1000
+
+type progressTracker struct {
+	callback func(Progress)
+	interval int
+	progress Progress
+	reported int
+}
+
+func newProgressTracker(options *Options) progressTracker {
+	interval := options.ProgressInterval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+
+	return progressTracker{callback: options.Progress, interval: interval, reported: -1}
+}
+
+// decoded counts a decoded record and reports every interval records
+func (p *progressTracker) decoded(line int, offset int64) {
+	if p.callback == nil {
+		return
+	}
+
+	p.progress.Records++
+	p.progress.Bytes = offset
+	p.progress.Line = line
+
+	if p.progress.Records%p.interval == 0 {
+		p.report()
+	}
+}
+
+// done reports the final progress, unless it is already reported
+func (p *progressTracker) done() {
+	if p.callback != nil && p.reported != p.progress.Records {
+		p.report()
+	}
+}
+
+func (p *progressTracker) report() {
+	p.reported = p.progress.Records
+	p.callback(p.progress)
+}
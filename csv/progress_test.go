@@ -0,0 +1,91 @@
+package csv
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoder_Progress(t *testing.T) {
+	data := "Name,Age\nBob,12\nAlice,13\nEve,14\nSally,15\nJohn,16\n"
+
+	tests := []struct {
+		name     string
+		interval int
+		want     []Progress
+	}{
+		{
+			name:     "Interval",
+			interval: 2,
+			want: []Progress{
+				{Records: 2, Bytes: 25, Line: 3},
+				{Records: 4, Bytes: 41, Line: 5},
+				{Records: 5, Bytes: 49, Line: 6},
+			},
+		},
+		{
+			name:     "EndOnInterval",
+			interval: 5,
+			want: []Progress{
+				{Records: 5, Bytes: 49, Line: 6},
+			},
+		},
+		{
+			name: "DefaultInterval",
+			want: []Progress{
+				{Records: 5, Bytes: 49, Line: 6},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []Progress{}
+			options := &Options{
+				ProgressInterval: tt.interval,
+				Progress: func(p Progress) {
+					got = append(got, p)
+				},
+			}
+
+			if err := Unmarshal(&[]Simple{}, options, []byte(data)); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Options.Progress called with %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_DecodeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	options := &Options{
+		ProgressInterval: 1,
+		Progress: func(p Progress) {
+			if p.Records == 2 {
+				cancel()
+			}
+		},
+	}
+
+	decoder, err := NewDecoder(strings.NewReader("Name,Age\nBob,12\nAlice,13\nEve,14\n"), options)
+	if err != nil {
+		t.Fatalf("NewDecoder() error = %v", err)
+	}
+
+	got := &[]Simple{}
+	if err := decoder.DecodeContext(ctx, got); err != context.Canceled {
+		t.Fatalf("Decoder.DecodeContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	// The remaining record is still there to be read
+	record, err := decoder.Reader().ReadRecord()
+	if err != nil {
+		t.Fatalf("Reader.ReadRecord() error = %v", err)
+	}
+	if name, _ := record.Get("Name"); name != "Eve" {
+		t.Errorf("Reader.ReadRecord() = %v, want Eve", name)
+	}
+}
@@ -1,7 +1,9 @@
 package csv
 
 import (
+	"bufio"
 	"encoding/csv"
+	"fmt"
 	"io"
 )
 
@@ -9,6 +11,78 @@ type csvReader interface {
 	Read() (csvRecord, error)
 }
 
+// projectedReader is a csvReader that can leave out the fields that are not needed.
+// A nil projection reads all the fields.
+type projectedReader interface {
+	readProjected(projection columnProjection) (csvRecord, error)
+}
+
+// columnProjection tells which fields of a record are needed
+type columnProjection []bool
+
+func (p columnProjection) needs(i int) bool {
+	return p == nil || (i < len(p) && p[i])
+}
+
+type fieldPositioner interface {
+	FieldPos(field int) (line, column int)
+}
+
+/*
+RecordReader is the interface that wraps the Read method.
+
+Read returns the next record as a slice of fields, and io.EOF when there are no more records.
+A *encoding/csv.Reader is a RecordReader, and so is anything else that can produce rows of text such as SQL result sets or spreadsheets.
+
+If the RecordReader also has a FieldPos(field int) (line, column int) method, it is used to report positions,
+and an InputOffset() int64 method is used to report the bytes consumed.
+*/
+type RecordReader interface {
+	Read() (record []string, err error)
+}
+
+type recordSource struct {
+	RecordReader
+}
+
+func (r recordSource) Read() (csvRecord, error) {
+	return r.readProjected(nil)
+}
+
+func (r recordSource) readProjected(projection columnProjection) (csvRecord, error) {
+	srecord, err := r.RecordReader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	record := make(csvRecord, len(srecord))
+	for i, field := range srecord {
+		if projection.needs(i) {
+			record[i] = []byte(field)
+		}
+	}
+
+	return record, nil
+}
+
+func (r recordSource) InputOffset() int64 {
+	if offsetter, ok := r.RecordReader.(inputOffsetter); ok {
+		return offsetter.InputOffset()
+	}
+	return 0
+}
+
+func (r recordSource) FieldPos(field int) (line, column int) {
+	if positioner, ok := r.RecordReader.(fieldPositioner); ok {
+		return positioner.FieldPos(field)
+	}
+	return 0, 0
+}
+
+type inputOffsetter interface {
+	InputOffset() int64
+}
+
 type csvRawReader struct {
 	*csv.Reader
 }
@@ -28,17 +102,206 @@ func newReader(r io.Reader, options *Options) *csvRawReader {
 		reader.LazyQuotes = options.LazyQuotes
 		reader.TrimLeadingSpace = options.TrimLeadingSpace
 	}
+	// The fields are copied into the csvRecord, so the string slice can be reused
+	reader.ReuseRecord = true
 	return reader
 }
 
 func (r *csvRawReader) Read() (record csvRecord, err error) {
+	return r.readProjected(nil)
+}
+
+func (r *csvRawReader) readProjected(projection columnProjection) (csvRecord, error) {
 	srecord, err := r.Reader.Read()
 	if err != nil {
 		return nil, err
 	}
-	for _, field := range srecord {
-		record = append(record, []byte(field))
+
+	record := make(csvRecord, len(srecord))
+	for i, field := range srecord {
+		if projection.needs(i) {
+			record[i] = []byte(field)
+		}
 	}
 
 	return record, nil
 }
+
+/*
+A Reader reads CSV records one at a time and keeps track of the headers and the position in the input.
+
+Use a Reader when the raw records are needed, and a Decoder when records should be mapped onto structs.
+*/
+type Reader struct {
+	reader     csvReader
+	headers    headerList
+	headerMap  headerMap
+	recordLine int
+	pending    *Record
+
+	// fileName and comma are kept in the records for their File and Raw methods
+	fileName string
+	comma    rune
+}
+
+/*
+NewReader returns a new Reader that reads from r.
+
+If options.Headers is nil the headers are expected to be in the first csv record.
+If options.Columns is set the input is read as fixed-width columns, and the headers default to the column names.
+If options.Sniff is set the format is guessed from the start of the input, see Sniff.
+*/
+func NewReader(r io.Reader, options *Options) (*Reader, error) {
+	if r == nil {
+		return nil, fmt.Errorf("reader can't be nil")
+	}
+
+	var headerlist []string
+	var noHeader bool
+	if options != nil {
+		headerlist = options.Headers
+		noHeader = options.NoHeader
+	}
+
+	if options != nil && options.Sniff {
+		// The sample stays in the buffer, so it is read again as records
+		buffered := bufio.NewReaderSize(r, sniffSampleSize)
+		sample, err := buffered.Peek(sniffSampleSize)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		sniffed, err := sniff(sample, len(sample) == sniffSampleSize)
+		if err != nil {
+			return nil, err
+		}
+
+		merged := *options
+		merged.Comma = sniffed.Comma
+		merged.Comment = sniffed.Comment
+		merged.LazyQuotes = sniffed.LazyQuotes
+		merged.NoHeader = sniffed.NoHeader
+		options = &merged
+		noHeader = merged.NoHeader
+		r = buffered
+	}
+
+	if options != nil && options.Columns != nil {
+		if err := validateColumns(options.Columns); err != nil {
+			return nil, err
+		}
+		if headerlist == nil {
+			headerlist = fixedWidthHeaders(options.Columns)
+		}
+		return newRecordReader(newFixedWidthReader(r, options), headerlist, false, options)
+	}
+
+	return newRecordReader(newReader(r, options), headerlist, noHeader, options)
+}
+
+func newRecordReader(r csvReader, headerlist []string, noHeader bool, options *Options) (*Reader, error) {
+	reader := &Reader{reader: r, comma: ','}
+	if options != nil {
+		reader.fileName = options.FileName
+		if options.Comma != 0 {
+			reader.comma = options.Comma
+		}
+	}
+
+	if headerlist == nil && noHeader && r != nil {
+		// The first record is data, it is read ahead to find the number of columns
+		first, err := reader.ReadRecord()
+		if err != nil {
+			return nil, err
+		}
+		headerlist = indexHeaders(first.Len())
+		reader.pending = first
+	}
+
+	headers, err := getHeaderList(r, headerlist)
+	if err != nil {
+		return nil, err
+	}
+
+	reader.headers = headers
+	reader.headerMap = headers.ToMap()
+	if reader.pending != nil {
+		reader.pending.headers = reader.headerMap
+	}
+
+	return reader, nil
+}
+
+/*
+Headers returns the column headers in the order they appear in the records.
+*/
+func (r *Reader) Headers() []string {
+	return append([]string(nil), r.headers...)
+}
+
+/*
+Line returns the line on which the most recently read record starts, or 0 if it is unknown.
+*/
+func (r *Reader) Line() int {
+	return r.recordLine
+}
+
+/*
+FieldPos returns the line and column of the start of the field with the given index in the most recently read record.
+Numbering of lines and columns starts at 1; columns are counted in bytes, not runes.
+
+If the position is unknown FieldPos returns 0, 0.
+*/
+func (r *Reader) FieldPos(field int) (line, column int) {
+	if positioner, ok := r.reader.(fieldPositioner); ok {
+		return positioner.FieldPos(field)
+	}
+	return 0, 0
+}
+
+/*
+InputOffset returns the number of bytes of the input consumed by the records read so far, or 0 if it is unknown.
+*/
+func (r *Reader) InputOffset() int64 {
+	if offsetter, ok := r.reader.(inputOffsetter); ok {
+		return offsetter.InputOffset()
+	}
+	return 0
+}
+
+/*
+ReadRecord reads the next record. At the end of the input ReadRecord returns nil, io.EOF.
+*/
+func (r *Reader) ReadRecord() (*Record, error) {
+	return r.readRecord(nil)
+}
+
+// readRecord reads the next record, leaving out the fields not in the projection if the underlying reader supports it
+func (r *Reader) readRecord(projection columnProjection) (*Record, error) {
+	if r.pending != nil {
+		record := r.pending
+		r.pending = nil
+		return record, nil
+	}
+
+	var fields csvRecord
+	var err error
+	if projected, ok := r.reader.(projectedReader); ok {
+		fields, err = projected.readProjected(projection)
+	} else {
+		fields, err = r.reader.Read()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordLine, _ = r.FieldPos(0)
+
+	return &Record{
+		fields:  fields,
+		headers: r.headerMap,
+		line:    r.recordLine,
+		file:    r.fileName,
+		comma:   r.comma,
+	}, nil
+}
@@ -1,7 +1,11 @@
 package csv
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -62,3 +66,157 @@ func Test_newReader(t *testing.T) {
 		})
 	}
 }
+
+func TestReader_ReadRecord(t *testing.T) {
+	reader, err := NewReader(strings.NewReader("Name,Age\nBob,12\n\"Alice\nSmith\",13\n"), nil)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	if got, want := reader.Headers(), []string{"Name", "Age"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader.Headers() = %v, want %v", got, want)
+	}
+
+	type row struct {
+		line int
+		name string
+		age  string
+	}
+	want := []row{
+		{2, "Bob", "12"},
+		{3, "Alice\nSmith", "13"},
+	}
+
+	for _, tt := range want {
+		record, err := reader.ReadRecord()
+		if err != nil {
+			t.Fatalf("Reader.ReadRecord() error = %v", err)
+		}
+		if record.Line() != tt.line || reader.Line() != tt.line {
+			t.Errorf("Record.Line() = %v, Reader.Line() = %v, want %v", record.Line(), reader.Line(), tt.line)
+		}
+		if name, _ := record.Get("Name"); name != tt.name {
+			t.Errorf("Record.Get(Name) = %q, want %q", name, tt.name)
+		}
+		if age := record.Field(1); age != tt.age {
+			t.Errorf("Record.Field(1) = %q, want %q", age, tt.age)
+		}
+		if _, found := record.Get("Missing"); found {
+			t.Errorf("Record.Get(Missing) found a field")
+		}
+	}
+
+	if line, column := reader.FieldPos(1); line != 4 || column != 8 {
+		t.Errorf("Reader.FieldPos(1) = %v, %v, want 4, 8", line, column)
+	}
+
+	if _, err := reader.ReadRecord(); err != io.EOF {
+		t.Errorf("Reader.ReadRecord() error = %v, want io.EOF", err)
+	}
+}
+
+func Test_csvRawReader_readProjected(t *testing.T) {
+	reader := newReader(strings.NewReader("a,b,c,d\ne,f,g,h\n"), nil)
+
+	tests := []struct {
+		name       string
+		projection columnProjection
+		want       csvRecord
+	}{
+		{
+			name:       "Projected",
+			projection: columnProjection{false, true, false},
+			want:       csvRecord{nil, []byte("b"), nil, nil},
+		},
+		{
+			name:       "All",
+			projection: nil,
+			want:       csvRecord{[]byte("e"), []byte("f"), []byte("g"), []byte("h")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reader.readProjected(tt.projection)
+			if err != nil {
+				t.Fatalf("readProjected() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readProjected() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func wideCSV(columns int, records int) []byte {
+	buffer := &bytes.Buffer{}
+	for i := 0; i < columns; i++ {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		fmt.Fprintf(buffer, "C%v", i)
+	}
+	buffer.WriteByte('\n')
+	for r := 0; r < records; r++ {
+		for i := 0; i < columns; i++ {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			fmt.Fprintf(buffer, "%v", r*i)
+		}
+		buffer.WriteByte('\n')
+	}
+	return buffer.Bytes()
+}
+
+type WideRecord struct {
+	First  int `csv:"C0"`
+	Second int `csv:"C10"`
+	Third  int `csv:"C50"`
+	Fourth int `csv:"C100"`
+	Fifth  int `csv:"C199"`
+}
+
+func BenchmarkReader_Projection(b *testing.B) {
+	data := wideCSV(200, 1000)
+
+	projection := make(columnProjection, 200)
+	for _, i := range []int{0, 10, 50, 100, 199} {
+		projection[i] = true
+	}
+
+	for _, bb := range []struct {
+		name       string
+		projection columnProjection
+	}{
+		{"AllColumns", nil},
+		{"Projected", projection},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				reader := newReader(bytes.NewReader(data), nil)
+				for {
+					if _, err := reader.readProjected(bb.projection); err == io.EOF {
+						break
+					} else if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode_Wide(b *testing.B) {
+	data := wideCSV(200, 1000)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		records := []WideRecord{}
+		if err := Unmarshal(&records, nil, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package csv
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+A Record is a single row read by a Reader. Fields can be looked up by their index or by their header.
+*/
+type Record struct {
+	fields  csvRecord
+	headers headerMap
+	line    int
+	file    string
+	comma   rune
+}
+
+/*
+Len returns the number of fields in the record.
+*/
+func (r *Record) Len() int {
+	return len(r.fields)
+}
+
+/*
+Line returns the line on which the record starts, or 0 if it is unknown.
+*/
+func (r *Record) Line() int {
+	return r.line
+}
+
+/*
+File returns the name of the input the record was read from, as given in Options.FileName.
+*/
+func (r *Record) File() string {
+	return r.file
+}
+
+/*
+Raw returns the fields of the record joined by the delimiter. The fields are not quoted again,
+so Raw is meant for keeping a copy of the row, not for parsing it.
+*/
+func (r *Record) Raw() string {
+	comma := r.comma
+	if comma == 0 {
+		comma = ','
+	}
+	return strings.Join(r.Strings(), string(comma))
+}
+
+/*
+Field returns the field with index i, or an empty string if the record has no such field.
+*/
+func (r *Record) Field(i int) string {
+	if i < 0 || i >= len(r.fields) {
+		return ""
+	}
+	return string(r.fields[i])
+}
+
+/*
+Get returns the field in the column with the given header. The boolean is false if the header is unknown or the record is too short.
+*/
+func (r *Record) Get(header string) (string, bool) {
+	i, found := r.headers[header]
+	if !found || i >= len(r.fields) {
+		return "", false
+	}
+	return string(r.fields[i]), true
+}
+
+/*
+Strings returns a copy of all the fields in the record.
+*/
+func (r *Record) Strings() []string {
+	fields := make([]string, len(r.fields))
+	for i, field := range r.fields {
+		fields[i] = string(field)
+	}
+	return fields
+}
+
+/*
+HasHeader reports whether the record has a column with the given header.
+*/
+func (r *Record) HasHeader(header string) bool {
+	_, found := r.headers[header]
+	return found
+}
+
+/*
+Lookup returns the field in the column with the given header, like Get, with the errors of the struct decoder.
+If the header is unknown it is an error when required is true, and otherwise found is false.
+If the record is too short to hold the column it is always an error.
+
+Lookup is meant for generated decoders, see RecordUnmarshaler.
+*/
+func (r *Record) Lookup(header string, required bool) (text string, found bool, err error) {
+	i, found := r.headers[header]
+	if !found {
+		if required {
+			return "", false, fmt.Errorf("required field i missing in header %v", header)
+		}
+		return "", false, nil
+	}
+
+	if i >= len(r.fields) {
+		return "", false, fmt.Errorf("not enough columns in record")
+	}
+
+	return string(r.fields[i]), true, nil
+}
+
+/*
+Set stores text in the column with the given header. It reports whether the header is known.
+*/
+func (r *Record) Set(header string, text string) bool {
+	i, found := r.headers[header]
+	if !found || i >= len(r.fields) {
+		return false
+	}
+	r.fields[i] = []byte(text)
+	return true
+}
+
+/*
+RecordUnmarshaler is implemented by structs that decode themselves from a record, such as the code generated by cmd/csvgen.
+When a pointer to the struct implements RecordUnmarshaler the Decoder calls DecodeCSV instead of using reflection,
+unless types or enums are registered with the Decoder or Options.Infer is set. DecodeCSV can't know about those,
+so the Decoder then uses reflection for all structs.
+*/
+type RecordUnmarshaler interface {
+	DecodeCSV(row *Record) error
+}
+
+/*
+RecordMarshaler is implemented by structs that encode themselves into a record, such as the code generated by cmd/csvgen.
+When a pointer to the struct implements RecordMarshaler the Encoder calls EncodeCSV with a record holding empty fields
+for all the headers, instead of using reflection. Like for RecordUnmarshaler, the Encoder uses reflection
+once types or enums are registered with it.
+*/
+type RecordMarshaler interface {
+	EncodeCSV(row *Record) error
+}
@@ -2,52 +2,99 @@ package csv
 
 import (
 	"fmt"
+	"reflect"
 )
 
 type recordDecoder struct {
 	decoders []*fieldDecoder
+	metas    []metaField
 	end      int
 }
 
-func newRecordDecoder(structType structType, headers headerMap) (*recordDecoder, error) {
+func newRecordDecoder(structType structType, headers headerMap, converters map[reflect.Type]objectUnmarshaler, transforms map[string]Transform) (*recordDecoder, error) {
 
 	decoders := []*fieldDecoder{}
+	metas := []metaField{}
 	last := 0
 
 	for i := 0; i < structType.NumField(); i++ {
 
-		field := getFieldInfo(structType.Field(i))
+		field, err := getFieldInfo(structType.Field(i))
+		if err != nil {
+			return nil, err
+		}
+
+		// Detail slices are filled by the grouping, one element per record
+		if _, found := field.Options["detail"]; found {
+			continue
+		}
+
+		// Meta fields hold where the record came from, not a column
+		if _, found := field.Options["meta"]; found {
+			meta, err := newMetaField(field)
+			if err != nil {
+				return nil, err
+			}
+			metas = append(metas, meta)
+			continue
+		}
 
 		csvIndex, found := headers[field.Name]
+		defaultValue, hasDefault := field.Options["default"]
 
 		if !found {
-			if field.IsOptional {
+			if !field.IsOptional {
+				return nil, fmt.Errorf("required field i missing in header %v", field.Name)
+			}
+			if !hasDefault {
 				continue
 			}
-			return nil, fmt.Errorf("required field i missing in header %v", field.Name)
+			csvIndex = -1
 		}
 
 		if csvIndex > last {
 			last = csvIndex
 		}
 
-		unmarshaller, err := structType.getUnmarshaler(field)
+		unmarshaller, err := structType.getUnmarshaler(field, converters)
+		if err != nil {
+			return nil, err
+		}
+
+		validators, err := getValidators(field)
+		if err != nil {
+			return nil, err
+		}
+
+		steps, err := getTransforms(field, transforms)
 		if err != nil {
 			return nil, err
 		}
 
+		// The default is converted once up front, so a broken default is found before any record
+		if hasDefault {
+			if err := unmarshaller.Unmarshal(reflect.New(field.Type).Interface(), []byte(defaultValue)); err != nil {
+				return nil, fmt.Errorf("invalid default %q for field %v: %v", defaultValue, field.Name, err)
+			}
+		}
+
 		decoders = append(
 			decoders,
 			&fieldDecoder{
+				name:         field.Name,
 				recordIndex:  csvIndex,
 				structIndex:  field.index,
 				unmarshaller: unmarshaller,
+				validators:   validators,
+				transforms:   steps,
+				defaultValue: []byte(defaultValue),
+				hasDefault:   hasDefault,
 			},
 		)
 
 	}
 
-	return &recordDecoder{decoders: decoders, end: last}, nil
+	return &recordDecoder{decoders: decoders, metas: metas, end: last}, nil
 }
 
 func (decoder recordDecoder) Unmarshal(object structRecord, record csvRecord) error {
@@ -63,3 +110,33 @@ func (decoder recordDecoder) Unmarshal(object structRecord, record csvRecord) er
 	}
 	return nil
 }
+
+// UnmarshalRecord stores the fields of the record in object, and fills the meta fields with where it came from
+func (decoder recordDecoder) UnmarshalRecord(object structRecord, record *Record) error {
+	if err := decoder.Unmarshal(object, record.fields); err != nil {
+		return err
+	}
+
+	for _, meta := range decoder.metas {
+		meta.decode(object, record)
+	}
+	return nil
+}
+
+// projection returns the fields of a record used by the decoder, or nil if all of them are
+func (decoder recordDecoder) projection() columnProjection {
+	// The raw row needs every field
+	for _, meta := range decoder.metas {
+		if meta.kind == "raw" {
+			return nil
+		}
+	}
+
+	projection := make(columnProjection, decoder.end+1)
+	for _, fieldDecoder := range decoder.decoders {
+		if fieldDecoder.recordIndex >= 0 {
+			projection[fieldDecoder.recordIndex] = true
+		}
+	}
+	return projection
+}
@@ -107,7 +107,7 @@ func Test_unmarshal(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dec := recordDecoder{tt.args.decoders, 1}
+			dec := recordDecoder{decoders: tt.args.decoders, end: 1}
 			if err := dec.Unmarshal(tt.args.object, tt.args.record); (err != nil) != tt.wantErr {
 				t.Errorf("unmarshal() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -0,0 +1,86 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type recordEncoder struct {
+	encoders []*fieldEncoder
+	width    int
+}
+
+func newRecordEncoder(structType structType, headers headerList, converters map[reflect.Type]objectMarshaler) (*recordEncoder, error) {
+
+	encoders := []*fieldEncoder{}
+	headermap := headers.ToMap()
+
+	for i := 0; i < structType.NumField(); i++ {
+
+		field, err := getFieldInfo(structType.Field(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, found := field.Options["detail"]; found {
+			return nil, fmt.Errorf("can't encode detail field %v", field.Name)
+		}
+
+		// Meta fields are filled when decoding and not written
+		if _, found := field.Options["meta"]; found {
+			continue
+		}
+
+		csvIndex, found := headermap[field.Name]
+
+		if !found {
+			if field.IsOptional {
+				continue
+			}
+			return nil, fmt.Errorf("required field i missing in header %v", field.Name)
+		}
+
+		marshaller, err := structType.getMarshaler(field, converters)
+		if err != nil {
+			return nil, err
+		}
+
+		encoders = append(
+			encoders,
+			&fieldEncoder{
+				recordIndex: csvIndex,
+				structIndex: field.index,
+				marshaller:  marshaller,
+			},
+		)
+
+	}
+
+	return &recordEncoder{encoders: encoders, width: len(headers)}, nil
+}
+
+func (encoder recordEncoder) Marshal(object structRecord) (csvRecord, error) {
+
+	record := make(csvRecord, encoder.width)
+
+	for _, fieldEncoder := range encoder.encoders {
+		if err := fieldEncoder.encode(object, record); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// getStructHeaders returns the headers of all the fields in the struct, in field order
+func getStructHeaders(structType structType) headerList {
+	headers := headerList{}
+	for i := 0; i < structType.NumField(); i++ {
+		// An invalid tag is reported when the encoder is created
+		field, _ := getFieldInfo(structType.Field(i))
+		if _, found := field.Options["meta"]; found {
+			continue
+		}
+		headers = append(headers, field.Name)
+	}
+	return headers
+}
@@ -0,0 +1,209 @@
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// sniffSampleSize is the number of bytes inspected by Sniff
+const sniffSampleSize = 64 * 1024
+
+// sniffHeaderRows is the number of data rows compared with the first row when looking for headers
+const sniffHeaderRows = 20
+
+// sniffCommas are the delimiters Sniff chooses between, in order of preference
+var sniffCommas = []rune{',', ';', '\t', '|'}
+
+/*
+Sniff inspects a sample from the start of r and proposes the Options to read it with, in the spirit of Python's csv.Sniffer.
+
+The proposal contains the Comma (one of ',', ';', '\t' and '|'), the Comment character ('#' or none),
+whether LazyQuotes is needed, and NoHeader if the first record looks like data rather than headers.
+
+The header guess is biased towards a header. When the columns give no evidence either way, as in files of
+text only, the first record is taken as the header. For a file without one, that drops the first row of data,
+so set NoHeader yourself when the files are known to have no header.
+
+Sniff consumes the sample from r. Use Options.Sniff to let NewDecoder sniff without losing the sampled data.
+*/
+func Sniff(r io.Reader) (*Options, error) {
+	sample := make([]byte, sniffSampleSize)
+
+	n, err := io.ReadFull(r, sample)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	return sniff(sample[:n], n == sniffSampleSize)
+}
+
+func sniff(sample []byte, truncated bool) (*Options, error) {
+	// A truncated sample ends in the middle of a line, which is left out
+	if truncated {
+		if i := bytes.LastIndexByte(sample, '\n'); i >= 0 {
+			sample = sample[:i+1]
+		}
+	}
+
+	options := &Options{
+		Comma:   ',',
+		Comment: sniffComment(sample),
+	}
+
+	bestConsistency, bestCount := 0.0, 1
+	for _, comma := range sniffCommas {
+		if comma == options.Comment {
+			continue
+		}
+
+		records, err := sniffRecords(sample, comma, options.Comment, true)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+
+		count, consistency := fieldCountMode(records)
+		if count < 2 {
+			continue
+		}
+
+		if consistency > bestConsistency || (consistency == bestConsistency && count > bestCount) {
+			options.Comma, bestConsistency, bestCount = comma, consistency, count
+		}
+	}
+
+	_, err := sniffRecords(sample, options.Comma, options.Comment, false)
+	options.LazyQuotes = needsLazyQuotes(err, truncated)
+
+	records, err := sniffRecords(sample, options.Comma, options.Comment, true)
+	if err != nil {
+		return nil, err
+	}
+	options.NoHeader = !hasHeader(records)
+
+	return options, nil
+}
+
+// sniffComment returns '#' if some, but not all, lines start with it
+func sniffComment(sample []byte) rune {
+	comments, lines := 0, 0
+	for _, line := range bytes.Split(sample, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		lines++
+		if line[0] == '#' {
+			comments++
+		}
+	}
+
+	if comments > 0 && comments < lines {
+		return '#'
+	}
+	return 0
+}
+
+func sniffRecords(sample []byte, comma rune, comment rune, lazyQuotes bool) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(sample))
+	reader.Comma = comma
+	reader.Comment = comment
+	reader.LazyQuotes = lazyQuotes
+	reader.FieldsPerRecord = -1
+
+	return reader.ReadAll()
+}
+
+// needsLazyQuotes reports whether a strict parse failed on quotes,
+// ignoring an open quoted field at the end of a truncated sample
+func needsLazyQuotes(err error, truncated bool) bool {
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) {
+		return false
+	}
+
+	switch parseErr.Err {
+	case csv.ErrBareQuote:
+		return true
+	case csv.ErrQuote:
+		return !truncated
+	}
+	return false
+}
+
+// fieldCountMode returns the most common number of fields and the share of records having it
+func fieldCountMode(records [][]string) (int, float64) {
+	counts := map[int]int{}
+	for _, record := range records {
+		counts[len(record)]++
+	}
+
+	mode, frequency := 0, 0
+	for count, n := range counts {
+		if n > frequency || (n == frequency && count > mode) {
+			mode, frequency = count, n
+		}
+	}
+
+	return mode, float64(frequency) / float64(len(records))
+}
+
+// hasHeader compares the first record with the following ones, a column votes for a header
+// if its data is numeric or of a fixed length and the first value is not. A tie is a header,
+// as most files have one and text columns give no vote.
+func hasHeader(records [][]string) bool {
+	if len(records) < 2 {
+		return true
+	}
+
+	header, rows := records[0], records[1:]
+	if len(rows) > sniffHeaderRows {
+		rows = rows[:sniffHeaderRows]
+	}
+
+	seen := map[string]bool{}
+	for _, name := range header {
+		if name == "" || seen[name] {
+			return false
+		}
+		seen[name] = true
+	}
+
+	votes := 0
+	for column, name := range header {
+		numeric, length := true, -1
+		for _, row := range rows {
+			if column >= len(row) {
+				numeric, length = false, -2
+				break
+			}
+			if _, err := strconv.ParseFloat(row[column], 64); err != nil {
+				numeric = false
+			}
+			switch {
+			case length == -1:
+				length = len(row[column])
+			case length != len(row[column]):
+				length = -2
+			}
+		}
+
+		switch {
+		case numeric:
+			if _, err := strconv.ParseFloat(name, 64); err != nil {
+				votes++
+			} else {
+				votes--
+			}
+		case length >= 0:
+			if len(name) != length {
+				votes++
+			} else {
+				votes--
+			}
+		}
+	}
+
+	return votes >= 0
+}
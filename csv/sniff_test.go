@@ -0,0 +1,163 @@
+package csv
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Options
+	}{
+		{
+			name: "Comma",
+			data: "Name,Age\nBob,12\nAlice,13\n",
+			want: Options{Comma: ','},
+		},
+		{
+			name: "Semicolon",
+			data: "Name;Age;Note\nBob;12;\"a, b\"\nAlice;13;\"c, d, e\"\n",
+			want: Options{Comma: ';'},
+		},
+		{
+			name: "Tab",
+			data: "Name\tAge\nBob\t12\nAlice\t13\n",
+			want: Options{Comma: '\t'},
+		},
+		{
+			name: "Pipe",
+			data: "Name|Age\nBob|12\nAlice|13\n",
+			want: Options{Comma: '|'},
+		},
+		{
+			name: "Comment",
+			data: "# exported today\nName,Age\nBob,12\n",
+			want: Options{Comma: ',', Comment: '#'},
+		},
+		{
+			name: "LazyQuotes",
+			data: "Name,Size\nBob,12\" pipe\nAlice,3\" pipe\n",
+			want: Options{Comma: ',', LazyQuotes: true},
+		},
+		{
+			name: "NoHeaderNumeric",
+			data: "Bob,12\nAlice,13\nEve,14\n",
+			want: Options{Comma: ',', NoHeader: true},
+		},
+		{
+			name: "NoHeaderFixedLength",
+			data: "DK,1\nSE,2\nNO,3\n",
+			want: Options{Comma: ',', NoHeader: true},
+		},
+		{
+			name: "HeaderFixedLength",
+			data: "Country,Code\nDK,1\nSE,2\n",
+			want: Options{Comma: ','},
+		},
+		{
+			name: "TieWithHeader",
+			data: "Name,City\nBob,Oslo\nAlice,Paris\n",
+			want: Options{Comma: ','},
+		},
+		{
+			// Text only data gives no evidence, and is taken as having a header
+			name: "TieWithoutHeader",
+			data: "Bob,Oslo\nAlice,Paris\nEve,Rome\n",
+			want: Options{Comma: ','},
+		},
+		{
+			name: "SingleColumn",
+			data: "Name\nBob\nAlice\n",
+			want: Options{Comma: ','},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sniff(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Sniff() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Sniff() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDecoder_Sniff(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		headers []string
+		want    *[]Simple
+	}{
+		{
+			name: "Header",
+			data: "# people\nAge;Name\n12;Bob\n13;Alice\n",
+			want: &[]Simple{{"Bob", 12}, {"Alice", 13}},
+		},
+		{
+			name:    "NoHeader",
+			data:    "Bob\t12\nAlice\t13\nEve\t14\n",
+			headers: SimpleHeaders,
+			want:    &[]Simple{{"Bob", 12}, {"Alice", 13}, {"Eve", 14}},
+		},
+		{
+			name: "LargeInput",
+			data: "Name|Age\n" + strings.Repeat("Bob|12\n", sniffSampleSize/7+10),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := NewDecoder(strings.NewReader(tt.data), &Options{Sniff: true, Headers: tt.headers})
+			if err != nil {
+				t.Fatalf("NewDecoder() error = %v", err)
+			}
+
+			got := &[]Simple{}
+			if err := decoder.Decode(got); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+
+			if tt.want == nil {
+				// Every record must be read, including the ones in the sample
+				if len(*got) != sniffSampleSize/7+10 {
+					t.Errorf("Decoder.Decode() decoded %v records, want %v", len(*got), sniffSampleSize/7+10)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decoder.Decode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReader_NoHeader(t *testing.T) {
+	reader, err := NewReader(strings.NewReader("Bob,12\nAlice,13\n"), &Options{NoHeader: true})
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	if got, want := reader.Headers(), []string{"0", "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader.Headers() = %v, want %v", got, want)
+	}
+
+	for _, want := range []string{"Bob", "Alice"} {
+		record, err := reader.ReadRecord()
+		if err != nil {
+			t.Fatalf("Reader.ReadRecord() error = %v", err)
+		}
+		if got, _ := record.Get("0"); got != want {
+			t.Errorf("Record.Get(0) = %v, want %v", got, want)
+		}
+	}
+
+	if _, err := reader.ReadRecord(); err != io.EOF {
+		t.Errorf("Reader.ReadRecord() error = %v, want io.EOF", err)
+	}
+}
@@ -11,12 +11,24 @@ var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(
 
 var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
 
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+
+var recordUnmarshalerType = reflect.TypeOf((*RecordUnmarshaler)(nil)).Elem()
+
+var recordMarshalerType = reflect.TypeOf((*RecordMarshaler)(nil)).Elem()
+
 var unmarshalFuncType = reflect.TypeOf(UnmarshalFunc(nil))
 
 var errorType = reflect.TypeOf((*error)(nil)).Elem()
 
 var bytesliceType = reflect.TypeOf([]byte{})
 
+type objectMarshaler interface {
+	Marshal(v interface{}) ([]byte, error)
+}
+
 type objectUnmarshaler interface {
 	Unmarshal(v interface{}, text []byte) error
 }
@@ -27,37 +39,136 @@ func (n nativeUnmarshaller) Unmarshal(v interface{}, text []byte) error {
 	return n(v, text)
 }
 
-func nativeUnmarshalQuoted(v interface{}, data []byte) error {
-	/*
-		Can't get an error from a string, unless an encoder is used
+func nativeUnmarshalUnquoted(v interface{}, data []byte) error {
+	return json.Unmarshal(data, v)
+}
 
-		String values encode as JSON strings coerced to valid UTF-8, replacing invalid bytes with the Unicode
-		replacement rune. So that the JSON will be safe to embed inside HTML <script> tags, the string is encoded using
-		HTMLEscape, which replaces "<", ">", "&", U+2028, and U+2029 are escaped to "\u003c","\u003e", "\u0026",
-		"\u2028", and "\u2029". This replacement can be disabled when using an Encoder, by calling
-		SetEscapeHTML(false).
-	*/
-	raw, _ := json.Marshal(string(data))
+func nativeUnmarshalText(v interface{}, data []byte) error {
+	return v.(encoding.TextUnmarshaler).UnmarshalText(data)
+}
 
-	return json.Unmarshal(raw, v)
+func nativeUnmarshalCSV(v interface{}, data []byte) error {
+	return v.(Unmarshaler).UnmarshalCSV(data)
 }
 
-func nativeUnmarshalUnquoted(v interface{}, data []byte) error {
-	return json.Unmarshal(data, v)
+// nativeUnmarshalPointer converts into a new value of the pointer type t with the method of the pointer,
+// and leaves the pointer nil for blank cells
+func nativeUnmarshalPointer(t reflect.Type, unmarshal nativeUnmarshaller) nativeUnmarshaller {
+	return func(v interface{}, data []byte) error {
+		target := reflect.ValueOf(v).Elem()
+		if len(data) == 0 {
+			target.Set(reflect.Zero(t))
+			return nil
+		}
+
+		elem := reflect.New(t.Elem())
+		if err := unmarshal(elem.Interface(), data); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
 }
 
 func nativeUnmarshal(t reflect.Type) nativeUnmarshaller {
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return nativeUnmarshaller(nativeUnmarshalCSV)
+	}
+	if t.Kind() == reflect.Ptr && t.Implements(unmarshalerType) {
+		return nativeUnmarshalPointer(t, nativeUnmarshalCSV)
+	}
+
+	if unmarshaller, ok := bigUnmarshal(t); ok {
+		return unmarshaller
+	}
+
 	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
-		return nativeUnmarshaller(nativeUnmarshalQuoted)
+		return nativeUnmarshaller(nativeUnmarshalText)
+	}
+	if t.Kind() == reflect.Ptr && t.Implements(textUnmarshalerType) {
+		return nativeUnmarshalPointer(t, nativeUnmarshalText)
 	}
 
-	if t.Kind() == reflect.String {
-		return nativeUnmarshaller(nativeUnmarshalQuoted)
+	if unmarshaller, ok := nativeUnmarshalKind(t); ok {
+		return unmarshaller
 	}
 
 	return nativeUnmarshaller(nativeUnmarshalUnquoted)
 }
 
+type nativeMarshaller func(v interface{}) ([]byte, error)
+
+func (n nativeMarshaller) Marshal(v interface{}) ([]byte, error) {
+	return n(v)
+}
+
+func nativeMarshalCSV(v interface{}) ([]byte, error) {
+	return v.(Marshaler).MarshalCSV()
+}
+
+func nativeMarshalText(v interface{}) ([]byte, error) {
+	return v.(encoding.TextMarshaler).MarshalText()
+}
+
+func nativeMarshalString(v interface{}) ([]byte, error) {
+	return []byte(reflect.ValueOf(v).Elem().String()), nil
+}
+
+func nativeMarshalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Types marshalling to a JSON string are written without the quotes, the same way they are read
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return nil, err
+		}
+		return []byte(text), nil
+	}
+
+	return data, nil
+}
+
+// nativeMarshalPointer writes the value of a pointer field with the method of the pointer, and nil as a blank cell
+func nativeMarshalPointer(marshal nativeMarshaller) nativeMarshaller {
+	return func(v interface{}) ([]byte, error) {
+		value := reflect.ValueOf(v).Elem()
+		if value.IsNil() {
+			return []byte{}, nil
+		}
+		return marshal(value.Interface())
+	}
+}
+
+func nativeMarshal(t reflect.Type) nativeMarshaller {
+	if reflect.PtrTo(t).Implements(marshalerType) {
+		return nativeMarshaller(nativeMarshalCSV)
+	}
+	if t.Kind() == reflect.Ptr && t.Implements(marshalerType) {
+		return nativeMarshalPointer(nativeMarshalCSV)
+	}
+
+	if marshaller, ok := bigMarshal(t); ok {
+		return marshaller
+	}
+
+	if reflect.PtrTo(t).Implements(textMarshalerType) {
+		return nativeMarshaller(nativeMarshalText)
+	}
+	if t.Kind() == reflect.Ptr && t.Implements(textMarshalerType) {
+		return nativeMarshalPointer(nativeMarshalText)
+	}
+
+	if t.Kind() == reflect.String {
+		return nativeMarshaller(nativeMarshalString)
+	}
+
+	return nativeMarshaller(nativeMarshalJSON)
+}
+
 func verifyMethodSignature(methodType reflect.Type, parentType reflect.Type, fieldType reflect.Type) error {
 
 	argsIn := []reflect.Type{
@@ -79,6 +190,27 @@ func verifyMethodSignature(methodType reflect.Type, parentType reflect.Type, fie
 	return nil
 }
 
+func verifyMarshalMethodSignature(methodType reflect.Type, parentType reflect.Type, fieldType reflect.Type) error {
+
+	argsIn := []reflect.Type{
+		reflect.PtrTo(parentType),
+		reflect.PtrTo(fieldType), // First args should be a pointer to the type we want to marshal
+	}
+
+	argsOut := []reflect.Type{
+		bytesliceType, // Return the text
+		errorType,     // and an error
+	}
+
+	wantedMethodType := reflect.FuncOf(argsIn, argsOut, false)
+
+	if wantedMethodType != methodType {
+		return fmt.Errorf("invalid method signature %v want %v", methodType, wantedMethodType)
+	}
+
+	return nil
+}
+
 type customUnmarshaler struct {
 	obj    reflect.Value
 	method reflect.Method
@@ -104,13 +236,46 @@ func (c customUnmarshaler) Unmarshal(v interface{}, text []byte) error {
 	return nil
 }
 
+type customMarshaler struct {
+	obj    reflect.Value
+	method reflect.Method
+}
+
+func (c customMarshaler) Marshal(v interface{}) ([]byte, error) {
+	// Prepare args
+	args := []reflect.Value{
+		c.obj,
+		reflect.ValueOf(v),
+	}
+
+	// Execute marshal
+	responses := c.method.Func.Call(args)
+
+	// Forward error if any
+	if err := responses[1].Interface(); err != nil {
+		return nil, err.(error)
+	}
+
+	return responses[0].Bytes(), nil
+}
+
 type structType struct {
 	reflect.Type
 }
 
-func (s structType) getUnmarshaler(field fieldInfo) (objectUnmarshaler, error) {
+/*
+getUnmarshaler returns the unmarshaler of the field. A method named in the tag comes first,
+then an enum declared in the tag, then a converter registered for the type, and then the native conversions.
+*/
+func (s structType) getUnmarshaler(field fieldInfo, converters map[reflect.Type]objectUnmarshaler) (objectUnmarshaler, error) {
 
 	if field.Unmarshal == "" {
+		if _, found := field.Options["enum"]; found {
+			return getTagEnum(field)
+		}
+		if converter, found := converters[field.Type]; found {
+			return converter, nil
+		}
 		return nativeUnmarshal(field.Type), nil
 	}
 
@@ -137,3 +302,40 @@ func (s structType) getUnmarshaler(field fieldInfo) (objectUnmarshaler, error) {
 		method: methodType,
 	}, nil
 }
+
+// getMarshaler returns the marshaler of the field, in the same order as getUnmarshaler
+func (s structType) getMarshaler(field fieldInfo, converters map[reflect.Type]objectMarshaler) (objectMarshaler, error) {
+
+	if field.Marshal == "" {
+		if _, found := field.Options["enum"]; found {
+			return getTagEnum(field)
+		}
+		if converter, found := converters[field.Type]; found {
+			return converter, nil
+		}
+		return nativeMarshal(field.Type), nil
+	}
+
+	// Verify that the method is not a value method
+	if invalidMethod, foundInvalid := s.Type.MethodByName(field.Marshal); foundInvalid {
+		return nil, fmt.Errorf("invalid method %v can't be value method", invalidMethod)
+	}
+
+	methodType, ok := reflect.PtrTo(s.Type).MethodByName(field.Marshal)
+	if !ok {
+		return nil, fmt.Errorf("invalid method name %v", field.Marshal)
+	}
+
+	// Verify method
+	if err := verifyMarshalMethodSignature(methodType.Type, s.Type, field.Type); err != nil {
+		return nil, err
+	}
+
+	// Create a zero value pointer (no reason to allocate object)
+	obj := reflect.Zero(reflect.PtrTo(s.Type))
+
+	return customMarshaler{
+		obj:    obj,
+		method: methodType,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package csv
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+/*
+A Transform rewrites the text of a cell before it is converted. param is the text after the colon in the tag,
+like the characters of strip:$, or an empty string. A Transform must not modify text, but may return a part of it.
+
+Transforms are listed in the transform option of the tag and run from left to right:
+
+	Price float64 `csv:"Price,transform='trim|strip:$,'"`
+
+The built in transforms are
+
+	trim, ltrim, rtrim  remove white space at both ends, the start or the end
+	upper, lower        change the case
+	strip:chars         remove all the characters in chars
+
+Blank cells left by the transforms get the default of the field, and validation rules check the transformed text.
+More transforms are given in Options.Transforms, or registered with Decoder.RegisterTransform.
+*/
+type Transform func(text []byte, param string) []byte
+
+var builtinTransforms = map[string]Transform{
+	"trim": func(text []byte, _ string) []byte {
+		return bytes.TrimSpace(text)
+	},
+	"ltrim": func(text []byte, _ string) []byte {
+		return bytes.TrimLeftFunc(text, unicode.IsSpace)
+	},
+	"rtrim": func(text []byte, _ string) []byte {
+		return bytes.TrimRightFunc(text, unicode.IsSpace)
+	},
+	"upper": func(text []byte, _ string) []byte {
+		return bytes.ToUpper(text)
+	},
+	"lower": func(text []byte, _ string) []byte {
+		return bytes.ToLower(text)
+	},
+	"strip": func(text []byte, chars string) []byte {
+		return bytes.Map(func(r rune) rune {
+			if strings.ContainsRune(chars, r) {
+				return -1
+			}
+			return r
+		}, text)
+	},
+}
+
+// transformStep is a transform of a field with its parameter
+type transformStep struct {
+	transform Transform
+	param     string
+}
+
+// getTransforms returns the steps of the transform option of the field
+func getTransforms(field fieldInfo, transforms map[string]Transform) ([]transformStep, error) {
+	option, found := field.Options["transform"]
+	if !found {
+		return nil, nil
+	}
+
+	steps := []transformStep{}
+	for _, step := range strings.Split(option, "|") {
+		name, param, _ := strings.Cut(step, ":")
+
+		transform, found := transforms[name]
+		if !found {
+			return nil, fmt.Errorf("unknown transform %v on field %v", name, field.Name)
+		}
+
+		steps = append(steps, transformStep{transform: transform, param: param})
+	}
+
+	return steps, nil
+}
+
+func applyTransforms(steps []transformStep, text []byte) []byte {
+	for _, step := range steps {
+		text = step.transform(text, step.param)
+	}
+	return text
+}
@@ -0,0 +1,143 @@
+package csv
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshal_Transform(t *testing.T) {
+	type price struct {
+		Code   string  `csv:"Code,transform=trim|upper"`
+		Amount float64 `csv:"Amount,transform='trim|strip:$,'"`
+		Note   string  `csv:"Note,transform=rtrim,default=none"`
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []price
+		wantErr bool
+	}{
+		{"transforms", "Code,Amount,Note\n dk ,\" $1,234.50 \",  kept  \n", []price{{"DK", 1234.5, "  kept"}}, false},
+		{"default after transform", "Code,Amount,Note\nse,1,   \n", []price{{"SE", 1, "none"}}, false},
+		{"still invalid", "Code,Amount,Note\nse,1.2.3,\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []price
+			err := Unmarshal(&rows, nil, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("got %+v, want %+v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_TransformUnknown(t *testing.T) {
+	type row struct {
+		Code string `csv:"Code,transform=trim|reverse"`
+	}
+
+	var rows []row
+	if err := Unmarshal(&rows, nil, []byte("Code\nx\n")); err == nil {
+		t.Error("expected an error for an unknown transform")
+	}
+}
+
+func TestDecoder_RegisterTransform(t *testing.T) {
+	type row struct {
+		Phone string `csv:"Phone,transform=prefix:+45|strip: "`
+	}
+
+	decoder, err := NewDecoder(strings.NewReader("Phone\n12 34 56 78\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder.RegisterTransform("prefix", func(text []byte, param string) []byte {
+		if bytes.HasPrefix(text, []byte(param)) {
+			return text
+		}
+		return append([]byte(param), text...)
+	})
+
+	var rows []row
+	if err := decoder.Decode(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].Phone != "+4512345678" {
+		t.Errorf("got %q, want %q", rows[0].Phone, "+4512345678")
+	}
+}
+
+// reverse is a transform that isn't built in, to check where custom transforms can be given
+func reverse(text []byte, _ string) []byte {
+	reversed := make([]byte, len(text))
+	for i, c := range text {
+		reversed[len(text)-1-i] = c
+	}
+	return reversed
+}
+
+type reversedCode struct {
+	Kind string
+	Code string `csv:"Code,transform=rev"`
+}
+
+func TestTypedDecoder_Transforms(t *testing.T) {
+	data := "Kind,Code\nA,cba\n"
+
+	if _, err := NewTypedDecoder[reversedCode](strings.NewReader(data), nil); err == nil {
+		t.Error("NewTypedDecoder() expected an error for an unknown transform")
+	}
+
+	decoder, err := NewTypedDecoder[reversedCode](strings.NewReader(data), &Options{Transforms: map[string]Transform{"rev": reverse}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decoder.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code != "abc" {
+		t.Errorf("got %q, want %q", got.Code, "abc")
+	}
+}
+
+func TestMultiDecoder_RegisterTransform(t *testing.T) {
+	data := "Kind,Code\nA,cba\n"
+
+	m, err := NewMultiDecoder(strings.NewReader(data), "Kind", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register("A", reversedCode{}, nil); err == nil {
+		t.Error("Register() expected an error for a transform registered after it")
+	}
+
+	m, err = NewMultiDecoder(strings.NewReader(data), "Kind", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterTransform("rev", reverse); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register("A", reversedCode{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for got, err := range m.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.(reversedCode).Code != "abc" {
+			t.Errorf("got %+v, want code %q", got, "abc")
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package csv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+)
+
+/*
+A TypedDecoder reads and decodes CSV records into values of the struct type T, one record at a time.
+*/
+type TypedDecoder[T any] struct {
+	decoder *Decoder
+}
+
+/*
+NewTypedDecoder returns a new decoder that reads values of type T from r. T must be a struct type.
+
+See NewDecoder for the meaning of the options. The fields of T are checked up front, so the transforms
+named in its tags must be built in or given in options.Transforms, and the enums and types converting its
+defaults must be given in options.Enums and options.Types.
+*/
+func NewTypedDecoder[T any](r io.Reader, options *Options) (*TypedDecoder[T], error) {
+	decoder, err := NewDecoder(r, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTypedDecoder[T](decoder)
+}
+
+func newTypedDecoder[T any](decoder *Decoder) (*TypedDecoder[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't decode into %v, want a struct", t)
+	}
+
+	// The record decoder is created up front, so invalid structs fail before reading
+	if err := decoder.prepareElement(t, "struct"); err != nil {
+		return nil, err
+	}
+
+	return &TypedDecoder[T]{decoder: decoder}, nil
+}
+
+/*
+Next reads the next record, or the next group of records if T has a detail field, and returns it decoded.
+At the end of the input Next returns io.EOF.
+*/
+func (d *TypedDecoder[T]) Next() (T, error) {
+	var value T
+
+	// Generated decoders, hooks and groups are handled the same way as by Decode
+	elem, err := d.decoder.nextElement(context.Background(), reflect.TypeOf(value))
+	if err == io.EOF {
+		d.decoder.progress.done()
+	}
+	if err != nil {
+		return value, err
+	}
+	return elem.Interface().(T), nil
+}
+
+/*
+RegisterEnum makes the decoder read fields of the type of enum by name, see Decoder.RegisterEnum.
+The defaults of T are converted by NewTypedDecoder, so enums used by defaults are given in Options.Enums instead.
+*/
+func (d *TypedDecoder[T]) RegisterEnum(enum *Enum) {
+	d.decoder.RegisterEnum(enum)
+}
+
+/*
+RegisterType makes the decoder read fields of the type of v with unmarshal, see Decoder.RegisterType.
+Types used by the defaults of T are given in Options.Types instead, see RegisterEnum.
+*/
+func (d *TypedDecoder[T]) RegisterType(v interface{}, unmarshal UnmarshalFunc) {
+	d.decoder.RegisterType(v, unmarshal)
+}
+
+/*
+All returns an iterator over the remaining records.
+
+The iteration stops after the first error, which is yielded together with the zero value of T.
+*/
+func (d *TypedDecoder[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			value, err := d.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(value, nil) {
+				return
+			}
+		}
+	}
+}
+
+/*
+DecodeToChannel decodes the remaining records and sends each one to ch as soon as it is decoded.
+
+It returns nil at the end of the input, the first decoding error, or the error of ctx if it is done first.
+DecodeToChannel does not close ch.
+*/
+func (d *TypedDecoder[T]) DecodeToChannel(ctx context.Context, ch chan<- T) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		value, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case ch <- value:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package csv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTypedDecoder_Next(t *testing.T) {
+	decoder, err := NewTypedDecoder[Simple](strings.NewReader("Name,Age\nBob,12\nAlice,13\n"), nil)
+	if err != nil {
+		t.Fatalf("NewTypedDecoder() error = %v", err)
+	}
+
+	for _, want := range []Simple{{"Bob", 12}, {"Alice", 13}} {
+		got, err := decoder.Next()
+		if err != nil {
+			t.Fatalf("TypedDecoder.Next() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("TypedDecoder.Next() = %v, want %v", got, want)
+		}
+	}
+
+	if _, err := decoder.Next(); err != io.EOF {
+		t.Errorf("TypedDecoder.Next() error = %v, want io.EOF", err)
+	}
+}
+
+func TestTypedDecoder_All(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Simple
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			data: "Name,Age\nBob,12\nAlice,13\n",
+			want: []Simple{{"Bob", 12}, {"Alice", 13}},
+		},
+		{
+			name:    "StopsAtError",
+			data:    "Name,Age\nBob,12\nAlice,three\nEve,14\n",
+			want:    []Simple{{"Bob", 12}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := NewTypedDecoder[Simple](strings.NewReader(tt.data), nil)
+			if err != nil {
+				t.Fatalf("NewTypedDecoder() error = %v", err)
+			}
+
+			got := []Simple{}
+			var gotErr error
+			for value, err := range decoder.All() {
+				if err != nil {
+					gotErr = err
+					continue
+				}
+				got = append(got, value)
+			}
+
+			if (gotErr != nil) != tt.wantErr {
+				t.Errorf("TypedDecoder.All() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TypedDecoder.All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTypedDecoder(t *testing.T) {
+	if _, err := NewTypedDecoder[int](strings.NewReader("Name\n"), nil); err == nil {
+		t.Errorf("NewTypedDecoder[int]() expected an error")
+	}
+
+	if _, err := NewTypedDecoder[ErrorMissingRequired](strings.NewReader("Age\n"), nil); err == nil {
+		t.Errorf("NewTypedDecoder() with a missing required field expected an error")
+	}
+}
+
+func TestTypedDecoder_DecodeToChannel(t *testing.T) {
+	decoder, err := NewTypedDecoder[Simple](strings.NewReader("Name,Age\nBob,12\nAlice,13\n"), nil)
+	if err != nil {
+		t.Fatalf("NewTypedDecoder() error = %v", err)
+	}
+
+	ch := make(chan Simple)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- decoder.DecodeToChannel(context.Background(), ch)
+		close(ch)
+	}()
+
+	got := []Simple{}
+	for value := range ch {
+		got = append(got, value)
+	}
+
+	if err := <-errs; err != nil {
+		t.Errorf("TypedDecoder.DecodeToChannel() error = %v", err)
+	}
+	if want := []Simple{{"Bob", 12}, {"Alice", 13}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TypedDecoder.DecodeToChannel() = %v, want %v", got, want)
+	}
+}
+
+func TestTypedDecoder_DecodeToChannelCanceled(t *testing.T) {
+	decoder, err := NewTypedDecoder[Simple](strings.NewReader("Name,Age\nBob,12\nAlice,13\n"), nil)
+	if err != nil {
+		t.Fatalf("NewTypedDecoder() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan Simple)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- decoder.DecodeToChannel(ctx, ch)
+	}()
+
+	if got := <-ch; got != (Simple{"Bob", 12}) {
+		t.Errorf("TypedDecoder.DecodeToChannel() = %v, want %v", got, Simple{"Bob", 12})
+	}
+
+	// Nobody receives the second record, so only the cancellation can end the call
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("TypedDecoder.DecodeToChannel() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// generatedAge rejects negative ages the way a generated decoder reports a field
+type generatedAge struct {
+	Age int
+}
+
+func (g *generatedAge) DecodeCSV(row *Record) error {
+	text, _ := row.Get("Age")
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return &RecordError{Column: "Age", Err: fmt.Errorf("invalid age %q", text)}
+	}
+	g.Age = n
+	return nil
+}
+
+func TestTypedDecoder_NextGenerated(t *testing.T) {
+	d, err := NewTypedDecoder[generatedAge](strings.NewReader("Age\n12\n-1\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := d.Next(); err != nil || got.Age != 12 {
+		t.Fatalf("Next() = %v, %v, want {12}", got, err)
+	}
+
+	_, err = d.Next()
+	var recordErr *RecordError
+	if !errors.As(err, &recordErr) || recordErr.Line != 3 || recordErr.Column != "Age" {
+		t.Errorf("Next() error = %v, want a record error on line 3, column Age", err)
+	}
+}
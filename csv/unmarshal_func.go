@@ -6,3 +6,8 @@ UnmarshalFunc is the method implemented by an object that can unmarshal a textua
 UnmarshalFunc must be able to decode the form generated by MarshalFunc. UnmarshalFunc must copy the text if it wishes to retain the text after returning.
 */
 type UnmarshalFunc func(v interface{}, text []byte) error
+
+// Unmarshal calls f, so registered functions are converters like the native ones
+func (f UnmarshalFunc) Unmarshal(v interface{}, text []byte) error {
+	return f(v, text)
+}
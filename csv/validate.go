@@ -0,0 +1,188 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+A ValidationError describes a field breaking one of the validation rules of its tag.
+
+The rules are options of the tag:
+
+	notempty      the cell must not be blank
+	min=n, max=n  the number must be at least or at most n
+	len=n         the string, slice, array or map must have length n
+	regex=expr    the cell must match the regular expression
+	oneof=a|b|c   the cell must be one of the values
+
+Parameters holding commas are quoted with single quotes, like regex='^[0-9]{1,3}$', and backslashes
+must be escaped as in any struct tag. The rules are checked after the field is converted, and the
+Decoder returns the error wrapped in a *RecordError with the line and the column.
+*/
+type ValidationError struct {
+	Rule  string
+	Param string
+	Value string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Param == "" {
+		return fmt.Sprintf("value %q breaks rule %v", e.Value, e.Rule)
+	}
+	return fmt.Sprintf("value %q breaks rule %v=%v", e.Value, e.Rule, e.Param)
+}
+
+// validator checks a converted field, value is the field and text the cell it was converted from
+type validator struct {
+	rule  string
+	param string
+	check func(value reflect.Value, text []byte) bool
+}
+
+func (v validator) validate(value reflect.Value, text []byte) error {
+	if v.check(value, text) {
+		return nil
+	}
+	return &ValidationError{Rule: v.rule, Param: v.param, Value: string(text)}
+}
+
+// getValidators returns the validators of the rules in the options of the field
+func getValidators(field fieldInfo) ([]validator, error) {
+	validators := []validator{}
+
+	for _, rule := range []string{"notempty", "min", "max", "len", "regex", "oneof"} {
+		param, found := field.Options[rule]
+		if !found {
+			continue
+		}
+
+		check, err := newCheck(rule, param, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %v on field %v: %v", rule, field.Name, err)
+		}
+
+		validators = append(validators, validator{rule: rule, param: param, check: check})
+	}
+
+	return validators, nil
+}
+
+func newCheck(rule string, param string, t reflect.Type) (func(reflect.Value, []byte) bool, error) {
+	switch rule {
+	case "notempty":
+		return func(_ reflect.Value, text []byte) bool {
+			return len(text) > 0
+		}, nil
+
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return nil, err
+		}
+		if !isNumber(indirectType(t)) {
+			return nil, fmt.Errorf("%v is not a number", t)
+		}
+		return func(value reflect.Value, _ []byte) bool {
+			n, ok := number(value)
+			if !ok {
+				return true
+			}
+			if rule == "min" {
+				return n >= limit
+			}
+			return n <= limit
+		}, nil
+
+	case "len":
+		length, err := strconv.Atoi(param)
+		if err != nil {
+			return nil, err
+		}
+		switch indirectType(t).Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		default:
+			return nil, fmt.Errorf("%v has no length", t)
+		}
+		return func(value reflect.Value, _ []byte) bool {
+			value, ok := indirect(value)
+			if !ok {
+				return true
+			}
+			if value.Kind() == reflect.String {
+				return utf8.RuneCountInString(value.String()) == length
+			}
+			return value.Len() == length
+		}, nil
+
+	case "regex":
+		expr, err := regexp.Compile(param)
+		if err != nil {
+			return nil, err
+		}
+		return func(_ reflect.Value, text []byte) bool {
+			return expr.Match(text)
+		}, nil
+
+	case "oneof":
+		values := strings.Split(param, "|")
+		return func(_ reflect.Value, text []byte) bool {
+			for _, v := range values {
+				if v == string(text) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown rule")
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// indirect follows the pointers of value, ok is false for a nil pointer
+func indirect(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return value, false
+		}
+		value = value.Elem()
+	}
+	return value, true
+}
+
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// number returns the value as a float64, ok is false for a nil pointer
+func number(value reflect.Value) (float64, bool) {
+	value, ok := indirect(value)
+	if !ok {
+		return 0, false
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	}
+	return value.Float(), true
+}
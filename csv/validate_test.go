@@ -0,0 +1,89 @@
+package csv
+
+import (
+	"errors"
+	"testing"
+)
+
+type Validated struct {
+	Code   string   `csv:"Code,,,,notempty,len=3"`
+	Age    int      `csv:"Age,,,,min=0,max=130"`
+	Score  *float64 `csv:"Score,,,,min=0.5"`
+	Status string   `csv:"Status,,,,oneof=new|done"`
+	Email  string   `csv:"Email,,,,regex=^[^@]+@[^@]+$"`
+	Tags   []string `csv:"Tags,,,,len=2"`
+}
+
+func TestUnmarshal_Validation(t *testing.T) {
+	const header = "Code,Age,Score,Status,Email,Tags\n"
+
+	tests := []struct {
+		name   string
+		record string
+		column string
+		rule   string
+	}{
+		{name: "valid", record: `ABC,42,0.5,new,bob@example.com,"[""a"",""b""]"`},
+		{name: "nil pointer", record: `ABC,42,null,done,bob@example.com,"[""a"",""b""]"`},
+		{name: "empty", record: `,42,1,new,bob@example.com,"[""a"",""b""]"`, column: "Code", rule: "notempty"},
+		{name: "string length", record: `ABCD,42,1,new,bob@example.com,"[""a"",""b""]"`, column: "Code", rule: "len"},
+		{name: "multibyte length", record: `ÆØÅ,42,1,new,bob@example.com,"[""a"",""b""]"`},
+		{name: "min", record: `ABC,-1,1,new,bob@example.com,"[""a"",""b""]"`, column: "Age", rule: "min"},
+		{name: "max", record: `ABC,131,1,new,bob@example.com,"[""a"",""b""]"`, column: "Age", rule: "max"},
+		{name: "min float", record: `ABC,42,0.4,new,bob@example.com,"[""a"",""b""]"`, column: "Score", rule: "min"},
+		{name: "oneof", record: `ABC,42,1,old,bob@example.com,"[""a"",""b""]"`, column: "Status", rule: "oneof"},
+		{name: "regex", record: `ABC,42,1,new,bob,"[""a"",""b""]"`, column: "Email", rule: "regex"},
+		{name: "slice length", record: `ABC,42,1,new,bob@example.com,"[""a""]"`, column: "Tags", rule: "len"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []Validated{}
+			err := Unmarshal(&got, nil, []byte(header+"ABC,1,1,new,a@b,\"[\"\"a\"\",\"\"b\"\"]\"\n"+tt.record+"\n"))
+
+			if tt.rule == "" {
+				if err != nil {
+					t.Errorf("Unmarshal() error = %v", err)
+				}
+				return
+			}
+
+			var recordErr *RecordError
+			var validationErr *ValidationError
+			if !errors.As(err, &recordErr) || !errors.As(err, &validationErr) {
+				t.Fatalf("Unmarshal() error = %v, want a validation error", err)
+			}
+			if recordErr.Line != 3 || recordErr.Column != tt.column || validationErr.Rule != tt.rule {
+				t.Errorf("Unmarshal() error = %v, want line 3, column %v, rule %v", err, tt.column, tt.rule)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_InvalidRule(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{name: "min on string", v: &[]struct {
+			A string `csv:"A,,,,min=1"`
+		}{}},
+		{name: "len on int", v: &[]struct {
+			A int `csv:"A,,,,len=1"`
+		}{}},
+		{name: "bad number", v: &[]struct {
+			A int `csv:"A,,,,max=ten"`
+		}{}},
+		{name: "bad regex", v: &[]struct {
+			A string `csv:"A,,,,regex=("`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unmarshal(tt.v, nil, []byte("A\n1\n")); err == nil {
+				t.Errorf("Unmarshal() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package csv
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter interface {
+	Write(record csvRecord) error
+	Flush() error
+}
+
+type csvRawWriter struct {
+	*csv.Writer
+}
+
+func newWriter(w io.Writer, options *Options) *csvRawWriter {
+	writer := &csvRawWriter{csv.NewWriter(w)}
+	if options != nil {
+		if options.Comma != 0 {
+			writer.Comma = options.Comma
+		}
+	}
+	return writer
+}
+
+func (w *csvRawWriter) Write(record csvRecord) error {
+	srecord := make([]string, len(record))
+	for i, field := range record {
+		srecord[i] = string(field)
+	}
+
+	return w.Writer.Write(srecord)
+}
+
+func (w *csvRawWriter) Flush() error {
+	w.Writer.Flush()
+	return w.Writer.Error()
+}
@@ -0,0 +1,184 @@
+/*
+Package tag parses the csv struct tag.
+
+The first element of the tag is the column name. It is followed by flags and key=value options in any
+order, and a value holding commas is quoted with single quotes:
+
+	csv:"Code,required,unmarshal=ParseCode,regex='^[A-Z]{2,3}$'"
+
+A name holding commas or surrounding spaces is quoted the same way, with the quotes in it doubled:
+
+	csv:"'Amount, EUR',required"
+
+The older positional form, name,unmarshal,marshal,required, is still read: exported method names in the
+second and third element are the unmarshal and marshal methods, and empty elements are skipped.
+*/
+package tag
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+Option is a flag, or a key=value option when HasValue is set.
+*/
+type Option struct {
+	Key      string
+	Value    string
+	HasValue bool
+}
+
+/*
+Tag is a parsed csv tag. The methods are given by the unmarshal and marshal options, or by position.
+*/
+type Tag struct {
+	Name      string
+	Unmarshal string
+	Marshal   string
+	Options   []Option
+}
+
+/*
+Parse splits the tag into the name, the methods and the options in the order they are given.
+*/
+func Parse(tag string) (Tag, error) {
+	elements, err := split(tag)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	parsed := Tag{Name: name(elements[0])}
+	seen := map[string]bool{}
+
+	for i, element := range elements[1:] {
+		slot := i + 1
+
+		if strings.Trim(element, " ") == "" {
+			continue
+		}
+
+		option := Option{Key: strings.Trim(element, " ")}
+		if eq := strings.Index(element, "="); eq >= 0 {
+			option = Option{Key: strings.Trim(element[:eq], " "), Value: unquote(element[eq+1:]), HasValue: true}
+		} else if slot <= 2 && isExported(option.Key) {
+			// Positional method names
+			option = Option{Key: [...]string{1: "unmarshal", 2: "marshal"}[slot], Value: option.Key, HasValue: true}
+		}
+
+		if seen[option.Key] {
+			return parsed, fmt.Errorf("option %v given twice", option.Key)
+		}
+		seen[option.Key] = true
+
+		switch option.Key {
+		case "unmarshal":
+			parsed.Unmarshal = option.Value
+		case "marshal":
+			parsed.Marshal = option.Value
+		default:
+			parsed.Options = append(parsed.Options, option)
+		}
+	}
+
+	return parsed, nil
+}
+
+// split splits the tag on the commas outside of quoted values
+func split(tag string) ([]string, error) {
+	elements := []string{}
+	start := 0
+	quoted := false
+
+	// A quoted name ends at the first quote that isn't doubled
+	if trimmed := strings.TrimLeft(tag, " "); strings.HasPrefix(trimmed, "'") {
+		end := nameEnd(tag, len(tag)-len(trimmed)+1)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated quote in %q", tag)
+		}
+
+		start = len(tag)
+		if comma := strings.IndexByte(tag[end:], ','); comma >= 0 {
+			start = end + comma
+		}
+		if strings.Trim(tag[end+1:start], " ") != "" {
+			return nil, fmt.Errorf("unexpected %q after the quoted name", strings.Trim(tag[end+1:start], " "))
+		}
+		if start == len(tag) {
+			return []string{tag}, nil
+		}
+
+		elements = append(elements, tag[:start])
+		start++
+	}
+
+	for i := start; i < len(tag); i++ {
+		switch {
+		case quoted:
+			// A quote only ends the value before a comma or at the end
+			if tag[i] == '\'' && (i+1 == len(tag) || tag[i+1] == ',') {
+				quoted = false
+			}
+		case tag[i] == '\'' && strings.HasSuffix(strings.TrimRight(tag[start:i], " "), "="):
+			quoted = true
+		case tag[i] == ',':
+			elements = append(elements, tag[start:i])
+			start = i + 1
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", tag[start:])
+	}
+
+	return append(elements, tag[start:]), nil
+}
+
+// nameEnd returns the index of the quote ending a quoted name, or -1 if it isn't ended
+func nameEnd(tag string, i int) int {
+	for ; i < len(tag); i++ {
+		if tag[i] != '\'' {
+			continue
+		}
+		if i+1 < len(tag) && tag[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// name returns the name in the first element, unquoted if it is quoted
+func name(element string) string {
+	trimmed := strings.Trim(element, " ")
+	if len(trimmed) >= 2 && trimmed[0] == '\'' && trimmed[len(trimmed)-1] == '\'' {
+		return strings.ReplaceAll(trimmed[1:len(trimmed)-1], "''", "'")
+	}
+	return trimmed
+}
+
+/*
+QuoteName returns name as the first element of a tag, quoted if it holds commas, quotes or surrounding spaces.
+*/
+func QuoteName(name string) string {
+	if !strings.ContainsAny(name, ",'") && strings.Trim(name, " ") == name {
+		return name
+	}
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
+
+func unquote(value string) string {
+	trimmed := strings.Trim(value, " ")
+	if len(trimmed) >= 2 && trimmed[0] == '\'' && trimmed[len(trimmed)-1] == '\'' {
+		return trimmed[1 : len(trimmed)-1]
+	}
+	return value
+}
+
+func isExported(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
@@ -0,0 +1,79 @@
+package xlsx
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+const timeLayout = time.RFC3339Nano
+
+// builtinDateFormats are the predefined number formats showing a date or a time
+var builtinDateFormats = map[int]bool{
+	14: true, 15: true, 16: true, 17: true, 18: true, 19: true, 20: true, 21: true, 22: true,
+	27: true, 28: true, 29: true, 30: true, 31: true, 32: true, 33: true, 34: true, 35: true, 36: true,
+	45: true, 46: true, 47: true,
+	50: true, 51: true, 52: true, 53: true, 54: true, 55: true, 56: true, 57: true, 58: true,
+}
+
+// dateStyles tells for each cell style if it formats the number as a date
+func dateStyles(styles styleSheet) []bool {
+	custom := map[int]bool{}
+	for _, format := range styles.NumFmts {
+		custom[format.ID] = isDateFormat(format.Code)
+	}
+
+	dates := make([]bool, len(styles.CellXfs))
+	for i, xf := range styles.CellXfs {
+		if isDate, found := custom[xf.NumFmtID]; found {
+			dates[i] = isDate
+		} else {
+			dates[i] = builtinDateFormats[xf.NumFmtID]
+		}
+	}
+	return dates
+}
+
+// isDateFormat reports whether a custom number format code contains date or time parts
+func isDateFormat(code string) bool {
+	var plain strings.Builder
+	quoted, bracket, escaped := false, false, false
+
+	for _, letter := range code {
+		switch {
+		case escaped:
+			escaped = false
+		case letter == '\\':
+			escaped = true
+		case letter == '"':
+			quoted = !quoted
+		case quoted:
+		case letter == '[':
+			bracket = true
+		case letter == ']':
+			bracket = false
+		case bracket:
+		default:
+			plain.WriteRune(letter)
+		}
+	}
+
+	return strings.ContainsAny(strings.ToLower(plain.String()), "ydhs")
+}
+
+// serialToTime converts an Excel date serial number to a time in UTC
+func serialToTime(serial float64, date1904 bool) time.Time {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	switch {
+	case date1904:
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	case serial < 60:
+		// Excel treats 1900 as a leap year, so serials before the non-existent 29 February are off by one
+		epoch = epoch.AddDate(0, 0, 1)
+	}
+
+	days := math.Floor(serial)
+	milliseconds := math.Round((serial - days) * 24 * 60 * 60 * 1000)
+
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(milliseconds) * time.Millisecond)
+}
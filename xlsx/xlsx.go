@@ -0,0 +1,437 @@
+/*
+Package xlsx reads the sheets of Excel XLSX workbooks as records, so they can be decoded with the same tagged structs as CSV files.
+
+Cells are converted to text the way they are displayed in a CSV export, except that cells formatted as dates are
+written in RFC 3339 format so they can be decoded into time.Time.
+*/
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/KalleDK/go-csv/csv"
+)
+
+const relationshipsNamespace = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+type workbook struct {
+	Properties struct {
+		Date1904 bool `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type richText struct {
+	T *string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r richText) String() string {
+	if r.T != nil {
+		return *r.T
+	}
+
+	var text strings.Builder
+	for _, run := range r.R {
+		text.WriteString(run.T)
+	}
+	return text.String()
+}
+
+type sharedStrings struct {
+	Items []richText `xml:"si"`
+}
+
+type styleSheet struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type row struct {
+	Cells []cell `xml:"c"`
+}
+
+type cell struct {
+	Ref    string    `xml:"r,attr"`
+	Type   string    `xml:"t,attr"`
+	Style  int       `xml:"s,attr"`
+	Value  string    `xml:"v"`
+	Inline *richText `xml:"is"`
+}
+
+/*
+A Reader reads the rows of a single sheet. It implements csv.RecordReader.
+
+Rows are padded with empty fields to the width of the first row, as spreadsheets leave out trailing empty cells.
+*/
+type Reader struct {
+	file     io.Closer
+	decoder  *xml.Decoder
+	strings  []string
+	dates    []bool
+	date1904 bool
+	width    int
+	line     int
+}
+
+/*
+Open opens the named XLSX file and returns a Reader for the given sheet. If sheet is empty the first sheet is read.
+
+The file is closed when the Reader is closed.
+*/
+func Open(name string, sheet string) (*Reader, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	reader, err := NewReader(file, info.Size(), sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	reader.file = multiCloser{reader.file, file}
+	return reader, nil
+}
+
+/*
+NewReader returns a Reader for the given sheet of the XLSX workbook in r, which has the given size.
+If sheet is empty the first sheet is read.
+*/
+func NewReader(r io.ReaderAt, size int64, sheet string) (*Reader, error) {
+	reader, content, err := openSheet(r, size, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	reader.file = content
+	reader.decoder = xml.NewDecoder(content)
+
+	return reader, nil
+}
+
+// openSheet reads the shared strings and styles of the workbook and opens the sheet, leaving the Reader without input
+func openSheet(r io.ReaderAt, size int64, sheet string) (*Reader, io.ReadCloser, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var book workbook
+	if err := readXML(files, "xl/workbook.xml", &book); err != nil {
+		return nil, nil, err
+	}
+
+	var rels relationships
+	if err := readXML(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, nil, err
+	}
+
+	sheetID := ""
+	for _, s := range book.Sheets {
+		if sheet == "" || s.Name == sheet {
+			sheetID = s.ID
+			break
+		}
+	}
+	if sheetID == "" {
+		return nil, nil, fmt.Errorf("sheet %q not found in workbook", sheet)
+	}
+
+	reader := &Reader{date1904: book.Properties.Date1904}
+	sheetPath := ""
+
+	for _, rel := range rels.Relationships {
+		target := relationshipTarget(rel.Target)
+		switch {
+		case rel.ID == sheetID:
+			sheetPath = target
+		case strings.HasSuffix(rel.Type, "/sharedStrings"):
+			var sst sharedStrings
+			if err := readXML(files, target, &sst); err != nil {
+				return nil, nil, err
+			}
+			reader.strings = make([]string, len(sst.Items))
+			for i, item := range sst.Items {
+				reader.strings[i] = item.String()
+			}
+		case strings.HasSuffix(rel.Type, "/styles"):
+			var styles styleSheet
+			if err := readXML(files, target, &styles); err != nil {
+				return nil, nil, err
+			}
+			reader.dates = dateStyles(styles)
+		}
+	}
+
+	file, found := files[sheetPath]
+	if !found {
+		return nil, nil, fmt.Errorf("sheet %q not found in archive", sheetPath)
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reader, content, nil
+}
+
+/*
+NewDecoder returns a csv.Decoder reading the given sheet of the XLSX workbook in r, which has the given size.
+
+Only options.Headers is used. If it is nil the headers are expected to be in the first row.
+
+The csv.Decoder has no Close, so the sheet is read into memory and closed before NewDecoder returns.
+Large sheets are streamed by a Reader passed to csv.NewDecoderFromRecords, closing the Reader when done.
+*/
+func NewDecoder(r io.ReaderAt, size int64, sheet string, options *csv.Options) (*csv.Decoder, error) {
+	reader, sheetContent, err := openSheet(r, size, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(sheetContent)
+	if closeErr := sheetContent.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reader.file = io.NopCloser(nil)
+	reader.decoder = xml.NewDecoder(bytes.NewReader(content))
+
+	return csv.NewDecoderFromRecords(reader, options)
+}
+
+/*
+Unmarshal parses the given sheet of the XLSX workbook in data and stores the result in the value pointed to by v.
+
+See the documentation for csv.Unmarshal for details about the conversion into a Go value.
+*/
+func Unmarshal(v interface{}, options *csv.Options, data []byte, sheet string) error {
+	reader, err := NewReader(bytes.NewReader(data), int64(len(data)), sheet)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	decoder, err := csv.NewDecoderFromRecords(reader, options)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(v)
+}
+
+/*
+Read returns the cells of the next row as text. At the end of the sheet Read returns nil, io.EOF.
+*/
+func (r *Reader) Read() ([]string, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var line int
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "r" {
+				line, _ = strconv.Atoi(attr.Value)
+			}
+		}
+		if line == 0 {
+			line = r.line + 1
+		}
+		r.line = line
+
+		var current row
+		if err := r.decoder.DecodeElement(&current, &start); err != nil {
+			return nil, err
+		}
+
+		record, err := r.record(current)
+		if err != nil {
+			return nil, err
+		}
+
+		// Rows without any cells are skipped the same way as empty lines in a CSV file
+		if len(record) == 0 {
+			continue
+		}
+
+		return record, nil
+	}
+}
+
+/*
+FieldPos returns the row number and the one-based column number of the field with the given index in the most recently read row.
+*/
+func (r *Reader) FieldPos(field int) (line, column int) {
+	return r.line, field + 1
+}
+
+/*
+Close closes the sheet, and the file if the Reader was created with Open.
+*/
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
+
+func (r *Reader) record(current row) ([]string, error) {
+	record := make([]string, 0, r.width)
+
+	for _, c := range current.Cells {
+		column := len(record)
+		if c.Ref != "" {
+			var err error
+			if column, err = columnIndex(c.Ref); err != nil {
+				return nil, err
+			}
+		}
+
+		for len(record) < column {
+			record = append(record, "")
+		}
+
+		text, err := r.text(c)
+		if err != nil {
+			return nil, fmt.Errorf("cell %v: %v", c.Ref, err)
+		}
+		record = append(record, text)
+	}
+
+	if r.width == 0 {
+		r.width = len(record)
+	}
+
+	for len(record) > 0 && len(record) < r.width {
+		record = append(record, "")
+	}
+
+	return record, nil
+}
+
+func (r *Reader) text(c cell) (string, error) {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(c.Value)
+		if err != nil || i < 0 || i >= len(r.strings) {
+			return "", fmt.Errorf("invalid shared string %q", c.Value)
+		}
+		return r.strings[i], nil
+	case "inlineStr":
+		if c.Inline == nil {
+			return "", nil
+		}
+		return c.Inline.String(), nil
+	case "b":
+		return strconv.FormatBool(c.Value == "1"), nil
+	case "", "n":
+		if c.Value != "" && c.Style >= 0 && c.Style < len(r.dates) && r.dates[c.Style] {
+			serial, err := strconv.ParseFloat(c.Value, 64)
+			if err != nil {
+				return "", err
+			}
+			return serialToTime(serial, r.date1904).Format(timeLayout), nil
+		}
+		return c.Value, nil
+	default:
+		// Formula strings (str), errors (e) and ISO dates (d) are already text
+		return c.Value, nil
+	}
+}
+
+type multiCloser []io.Closer
+
+func (closers multiCloser) Close() error {
+	var first error
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func readXML(files map[string]*zip.File, name string, v interface{}) error {
+	file, found := files[name]
+	if !found {
+		return fmt.Errorf("%v not found in archive", name)
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	return xml.NewDecoder(content).Decode(v)
+}
+
+// relationshipTarget returns the archive path of a target relative to the workbook
+func relationshipTarget(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+// columnIndex returns the zero-based column of a cell reference such as AB12
+func columnIndex(ref string) (int, error) {
+	column := 0
+	for i, letter := range ref {
+		if letter < 'A' || letter > 'Z' {
+			if i == 0 {
+				break
+			}
+			return column - 1, nil
+		}
+		column = column*26 + int(letter-'A'+1)
+	}
+	return 0, fmt.Errorf("invalid cell reference %q", ref)
+}
@@ -0,0 +1,246 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newWorkbook(t *testing.T, date1904 bool, sheets map[string]string) []byte {
+	t.Helper()
+
+	workbookPr := ""
+	if date1904 {
+		workbookPr = `<workbookPr date1904="1"/>`
+	}
+
+	sheetList := ""
+	rels := `<Relationship Id="rIdStrings" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>` +
+		`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="/xl/styles.xml"/>`
+	files := map[string]string{
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>Name</t></si><si><t>Age</t></si><si><t>Born</t></si><si><t>Active</t></si>` +
+			`<si><r><t>Bo</t></r><r><t>b</t></r></si></sst>`,
+		"xl/styles.xml": `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<numFmts><numFmt numFmtId="164" formatCode="yyyy\-mm\-dd"/><numFmt numFmtId="165" formatCode="&quot;day&quot;0"/></numFmts>` +
+			`<cellXfs><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="165"/></cellXfs></styleSheet>`,
+	}
+
+	for _, name := range []string{"First", "Second"} {
+		data, found := sheets[name]
+		if !found {
+			continue
+		}
+		sheetList += `<sheet name="` + name + `" sheetId="1" r:id="rId` + name + `"/>`
+		rels += `<Relationship Id="rId` + name + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/` + name + `.xml"/>`
+		files["xl/worksheets/"+name+".xml"] = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + data + `</sheetData></worksheet>`
+	}
+
+	files["xl/workbook.xml"] = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		workbookPr + `<sheets>` + sheetList + `</sheets></workbook>`
+	files["xl/_rels/workbook.xml.rels"] = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels + `</Relationships>`
+
+	buffer := &bytes.Buffer{}
+	archive := zip.NewWriter(buffer)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+var peopleSheet = `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c></row>` +
+	`<row r="2"><c r="A2" t="s"><v>4</v></c><c r="B2"><v>12</v></c><c r="C2" s="1"><v>43831.5</v></c><c r="D2" t="b"><v>1</v></c></row>` +
+	`<row r="4"><c r="A4" t="inlineStr"><is><t>Alice</t></is></c><c r="C4" s="2"><v>43832</v></c><c r="D4" t="b"><v>0</v></c></row>` +
+	`<row r="5"></row>`
+
+type Person struct {
+	Name   string
+	Age    string
+	Born   time.Time
+	Active bool
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := newWorkbook(t, false, map[string]string{
+		"First":  `<row><c t="inlineStr"><is><t>Other</t></is></c></row>`,
+		"Second": peopleSheet,
+	})
+
+	tests := []struct {
+		name    string
+		sheet   string
+		v       interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:  "NamedSheet",
+			sheet: "Second",
+			v:     &[]Person{},
+			want: &[]Person{
+				{"Bob", "12", time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC), true},
+				{"Alice", "", time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), false},
+			},
+		},
+		{
+			name:  "FirstSheet",
+			sheet: "",
+			v: &[]struct {
+				Other string
+			}{},
+			want: &[]struct {
+				Other string
+			}{},
+		},
+		{
+			name:    "MissingSheet",
+			sheet:   "Third",
+			v:       &[]Person{},
+			want:    &[]Person{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unmarshal(tt.v, nil, data, tt.sheet); (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.v, tt.want) {
+				t.Errorf("Unmarshal() = %v, want %v", tt.v, tt.want)
+			}
+		})
+	}
+}
+
+// The sheet is closed by NewDecoder, so decoding reads the copy in memory
+func TestNewDecoder(t *testing.T) {
+	data := newWorkbook(t, false, map[string]string{"First": peopleSheet})
+
+	decoder, err := NewDecoder(bytes.NewReader(data), int64(len(data)), "First", nil)
+	if err != nil {
+		t.Fatalf("NewDecoder() error = %v", err)
+	}
+
+	got := []Person{}
+	if err := decoder.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "Bob" || got[1].Name != "Alice" {
+		t.Errorf("Decode() = %v", got)
+	}
+}
+
+func TestReader_Read(t *testing.T) {
+	data := newWorkbook(t, false, map[string]string{"First": peopleSheet})
+
+	reader, err := NewReader(bytes.NewReader(data), int64(len(data)), "First")
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	defer reader.Close()
+
+	want := []struct {
+		line   int
+		record []string
+	}{
+		{1, []string{"Name", "Age", "Born", "Active"}},
+		{2, []string{"Bob", "12", "2020-01-01T12:00:00Z", "true"}},
+		{4, []string{"Alice", "", "2020-01-02T00:00:00Z", "false"}},
+	}
+	for _, tt := range want {
+		got, err := reader.Read()
+		if err != nil {
+			t.Fatalf("Reader.Read() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, tt.record) {
+			t.Errorf("Reader.Read() = %q, want %q", got, tt.record)
+		}
+		if line, _ := reader.FieldPos(0); line != tt.line {
+			t.Errorf("Reader.FieldPos() line = %v, want %v", line, tt.line)
+		}
+	}
+
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("Reader.Read() error = %v, want io.EOF", err)
+	}
+}
+
+func Test_serialToTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		serial   float64
+		date1904 bool
+		want     time.Time
+	}{
+		{"Modern", 43831, false, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"TimeOfDay", 43831.25, false, time.Date(2020, 1, 1, 6, 0, 0, 0, time.UTC)},
+		{"BeforeLeapBug", 1, false, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"AfterLeapBug", 61, false, time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Date1904", 0, true, time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serialToTime(tt.serial, tt.date1904); !got.Equal(tt.want) {
+				t.Errorf("serialToTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isDateFormat(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{`yyyy\-mm\-dd`, true},
+		{`[$-409]h:mm AM/PM`, true},
+		{`"day"0`, false},
+		{`[Red]#,##0.00`, false},
+		{`General`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := isDateFormat(tt.code); got != tt.want {
+				t.Errorf("isDateFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_columnIndex(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{"A1", 0, false},
+		{"Z9", 25, false},
+		{"AB12", 27, false},
+		{"12", 0, true},
+		{"AB", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := columnIndex(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("columnIndex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("columnIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}